@@ -0,0 +1,213 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+// pageDialog is the page name used for dialogs shown over the current view
+const pageDialog = "dialog"
+
+// processActions shows the dialogs for signalling and reprioritising a process
+type processActions struct {
+	app       *tview.Application
+	pages     *tview.Pages
+	returnTo  tview.Primitive // focused again once a dialog closes
+	setStatus func(text string)
+}
+
+// centered wraps a primitive so it is drawn at a fixed size in the middle of the screen
+func centered(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+func (a *processActions) show(p tview.Primitive, width, height int) {
+	a.pages.AddPage(pageDialog, centered(p, width, height), true, true)
+	a.app.SetFocus(p)
+}
+
+func (a *processActions) close() {
+	a.pages.RemovePage(pageDialog)
+	a.app.SetFocus(a.returnTo)
+}
+
+// dialogOpen reports whether a dialog is taking keyboard input
+func (a *processActions) dialogOpen() bool {
+	return a.pages.HasPage(pageDialog)
+}
+
+// confirm asks a yes/no question and runs action on yes, reporting the outcome
+func (a *processActions) confirm(question, success string, action func() error) {
+	modal := tview.NewModal().
+		SetText(question).
+		AddButtons([]string{"Yes", "No"}).
+		SetDoneFunc(func(index int, label string) {
+			a.close()
+			if label != "Yes" {
+				a.setStatus("[gray]Cancelled[-]")
+				return
+			}
+			if err := action(); err != nil {
+				a.showError(err)
+				return
+			}
+			a.setStatus("[green]" + tview.Escape(success) + "[-]")
+		})
+	a.pages.AddPage(pageDialog, modal, true, true)
+	a.app.SetFocus(modal)
+}
+
+func (a *processActions) showError(err error) {
+	modal := tview.NewModal().
+		SetText("Error: " + err.Error()).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(int, string) { a.close() })
+	modal.SetBackgroundColor(tcell.ColorDarkRed)
+	a.setStatus("[red]" + tview.Escape(err.Error()) + "[-]")
+	a.pages.AddPage(pageDialog, modal, true, true)
+	a.app.SetFocus(modal)
+}
+
+// handleKey opens the dialog for an action key (K, I or O) on p, reporting whether it was one.
+// Only the capitals count, since lowercase j/k move the selection in tview's tables.
+func (a *processActions) handleKey(event *tcell.EventKey, p metrics.ProcessInfo) bool {
+	switch event.Rune() {
+	case 'K':
+		a.signalMenu(p)
+	case 'I':
+		a.reniceForm(p)
+	case 'O':
+		a.ioniceForm(p)
+	default:
+		return false
+	}
+	return true
+}
+
+func describe(p metrics.ProcessInfo) string {
+	return fmt.Sprintf("PID %d (%s)", p.PID, p.Name)
+}
+
+// signalMenu lets the user pick a signal, or type any signal number, for a process
+func (a *processActions) signalMenu(p metrics.ProcessInfo) {
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true)
+	list.SetTitle(" Signal " + tview.Escape(describe(p)) + " ")
+
+	send := func(name string, number int) {
+		a.close()
+		a.confirm(
+			fmt.Sprintf("Send %s to %s?", name, describe(p)),
+			fmt.Sprintf("Sent %s to %s", name, describe(p)),
+			func() error { return metrics.SignalProcess(p.PID, number) },
+		)
+	}
+	for i, sig := range metrics.ProcessSignals {
+		shortcut := rune(0)
+		if i < 9 {
+			shortcut = rune('1' + i)
+		}
+		list.AddItem(fmt.Sprintf("%s (%d)", sig.Name, sig.Number), "", shortcut, func() { send(sig.Name, sig.Number) })
+	}
+	list.AddItem("Other signal number...", "", 'o', func() {
+		a.close()
+		a.numberPrompt("Signal number", "", func(number int) {
+			send(fmt.Sprintf("signal %d", number), number)
+		})
+	})
+	list.AddItem("Cancel", "", 'c', a.close)
+	list.SetDoneFunc(a.close)
+
+	a.show(list, 40, len(metrics.ProcessSignals)+4)
+}
+
+// numberPrompt asks for an integer and passes it to done
+func (a *processActions) numberPrompt(label, initial string, done func(int)) {
+	form := tview.NewForm()
+	form.SetBorder(true)
+	form.AddInputField(label, initial, 8, func(text string, last rune) bool {
+		return text == "-" || isInt(text)
+	}, nil)
+	form.AddButton("OK", func() {
+		text := form.GetFormItem(0).(*tview.InputField).GetText()
+		number, err := strconv.Atoi(text)
+		a.close()
+		if err != nil {
+			a.showError(fmt.Errorf("%q is not a number", text))
+			return
+		}
+		done(number)
+	})
+	form.AddButton("Cancel", a.close)
+	form.SetCancelFunc(a.close)
+	a.show(form, 40, 7)
+}
+
+func isInt(text string) bool {
+	_, err := strconv.Atoi(text)
+	return err == nil
+}
+
+// reniceForm asks for a new nice value for a process
+func (a *processActions) reniceForm(p metrics.ProcessInfo) {
+	a.numberPrompt("Nice (-20..19)", strconv.Itoa(int(p.Nice)), func(nice int) {
+		if nice < -20 || nice > 19 {
+			a.showError(fmt.Errorf("nice value %d is outside -20..19", nice))
+			return
+		}
+		a.confirm(
+			fmt.Sprintf("Change nice of %s from %d to %d?", describe(p), p.Nice, nice),
+			fmt.Sprintf("Set nice of %s to %d", describe(p), nice),
+			func() error { return metrics.SetNice(p.PID, nice) },
+		)
+	})
+}
+
+// ioniceForm asks for an I/O scheduling class and level for a process
+func (a *processActions) ioniceForm(p metrics.ProcessInfo) {
+	classes := []metrics.IOPriorityClass{metrics.IOPriorityBestEffort, metrics.IOPriorityRealtime, metrics.IOPriorityIdle}
+	classNames := []string{"best-effort", "realtime", "idle"}
+
+	form := tview.NewForm()
+	form.SetBorder(true)
+	form.SetTitle(" I/O priority " + tview.Escape(describe(p)) + " ")
+	form.AddDropDown("Class", classNames, 0, nil)
+	form.AddInputField("Level (0..7)", "4", 4, func(text string, last rune) bool { return isInt(text) }, nil)
+	form.AddButton("Apply", func() {
+		classIndex, className := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		text := form.GetFormItem(1).(*tview.InputField).GetText()
+		level, err := strconv.Atoi(text)
+		a.close()
+		if text == "" {
+			a.showError(errors.New("level is required"))
+			return
+		}
+		if err != nil {
+			a.showError(fmt.Errorf("%q is not a number", text))
+			return
+		}
+		if level < 0 || level > 7 {
+			a.showError(fmt.Errorf("I/O priority level %d is outside 0..7", level))
+			return
+		}
+		a.confirm(
+			fmt.Sprintf("Set I/O priority of %s to %s level %d?", describe(p), className, level),
+			fmt.Sprintf("Set I/O priority of %s to %s level %d", describe(p), className, level),
+			func() error { return metrics.SetIOPriority(p.PID, classes[classIndex], level) },
+		)
+	})
+	form.AddButton("Cancel", a.close)
+	form.SetCancelFunc(a.close)
+	a.show(form, 50, 9)
+}
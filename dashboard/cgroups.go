@@ -0,0 +1,120 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+// cgroupRow is one line of a cgroup table; name identifies it and is what the NAME column
+// shows, unit and slice are set for systemd units
+type cgroupRow struct {
+	metrics.CgroupStats
+	name        string
+	unit, slice string
+}
+
+// cgroupColumn is one column of a cgroup table
+type cgroupColumn = tableColumn[cgroupRow]
+
+// formatLimitBytes shows a cgroup memory limit, which is often unset
+func formatLimitBytes(limit uint64) string {
+	if limit == metrics.Unlimited {
+		return "max"
+	}
+	return formatBytes(limit)
+}
+
+var (
+	cgroupCPUColumn = cgroupColumn{"CPU%", tview.AlignRight,
+		func(r cgroupRow) string { return fmt.Sprintf("%.1f", r.CPUPercent) },
+		func(a, b cgroupRow) bool { return a.CPUPercent < b.CPUPercent }}
+	cgroupMemoryColumn = cgroupColumn{"MEMORY", tview.AlignRight,
+		func(r cgroupRow) string { return formatBytes(r.MemoryCurrent) },
+		func(a, b cgroupRow) bool { return a.MemoryCurrent < b.MemoryCurrent }}
+	cgroupTasksColumn = cgroupColumn{"TASKS", tview.AlignRight,
+		func(r cgroupRow) string { return fmt.Sprint(r.Tasks) },
+		func(a, b cgroupRow) bool { return a.Tasks < b.Tasks }}
+	cgroupReadColumn = cgroupColumn{"READ/s", tview.AlignRight,
+		func(r cgroupRow) string { return formatBytes(uint64(r.IOReadBytesPerSec)) },
+		func(a, b cgroupRow) bool { return a.IOReadBytesPerSec < b.IOReadBytesPerSec }}
+	cgroupWriteColumn = cgroupColumn{"WRITE/s", tview.AlignRight,
+		func(r cgroupRow) string { return formatBytes(uint64(r.IOWriteBytesPerSec)) },
+		func(a, b cgroupRow) bool { return a.IOWriteBytesPerSec < b.IOWriteBytesPerSec }}
+	cgroupNameColumn = cgroupColumn{"NAME", tview.AlignLeft,
+		func(r cgroupRow) string { return r.name },
+		func(a, b cgroupRow) bool { return a.name < b.name }}
+)
+
+// cgroupColumns are the columns of the cgroup ranking
+var cgroupColumns = []cgroupColumn{
+	cgroupCPUColumn,
+	{"THROT%", tview.AlignRight,
+		func(r cgroupRow) string { return fmt.Sprintf("%.1f", r.ThrottledPercent) },
+		func(a, b cgroupRow) bool { return a.ThrottledPercent < b.ThrottledPercent }},
+	{"THROTTLED", tview.AlignRight,
+		func(r cgroupRow) string { return fmt.Sprintf("%.1fs", float64(r.ThrottledUsec)/1e6) },
+		func(a, b cgroupRow) bool { return a.ThrottledUsec < b.ThrottledUsec }},
+	cgroupMemoryColumn,
+	{"MEM MAX", tview.AlignRight,
+		func(r cgroupRow) string { return formatLimitBytes(r.MemoryMax) },
+		func(a, b cgroupRow) bool { return a.MemoryMax < b.MemoryMax }},
+	{"MEM HIGH", tview.AlignRight,
+		func(r cgroupRow) string { return formatLimitBytes(r.MemoryHigh) },
+		func(a, b cgroupRow) bool { return a.MemoryHigh < b.MemoryHigh }},
+	{"OOM", tview.AlignRight,
+		func(r cgroupRow) string { return fmt.Sprint(r.MemoryEvents.OOM) },
+		func(a, b cgroupRow) bool { return a.MemoryEvents.OOM < b.MemoryEvents.OOM }},
+	{"KILLS", tview.AlignRight,
+		func(r cgroupRow) string { return fmt.Sprint(r.MemoryEvents.OOMKill) },
+		func(a, b cgroupRow) bool { return a.MemoryEvents.OOMKill < b.MemoryEvents.OOMKill }},
+	cgroupReadColumn,
+	cgroupWriteColumn,
+	cgroupTasksColumn,
+	cgroupNameColumn,
+}
+
+// newCgroupTable returns a sortable table of cgroups, kept in place across updates by name.
+// Rows are red once the OOM killer has struck or they spend half their periods throttled.
+func newCgroupTable(title string, columns []cgroupColumn) *sortableTable[cgroupRow] {
+	t := newSortableTable(title, columns,
+		func(r cgroupRow) string { return r.name },
+		func(a, b cgroupRow) bool { return a.name < b.name })
+	t.color = func(r cgroupRow) tcell.Color {
+		if r.MemoryEvents.OOMKill > 0 || r.ThrottledPercent >= 50 {
+			return tcell.ColorRed
+		}
+		return tcell.ColorDefault
+	}
+	t.shortcuts = map[rune]sortShortcut{
+		'c': {"CPU%", true}, 'C': {"CPU%", true},
+		'm': {"MEMORY", true}, 'M': {"MEMORY", true},
+	}
+	return t
+}
+
+// cgroupRows ranks every cgroup but the root, which would always top the list
+func cgroupRows(cgroups []metrics.CgroupStats) []cgroupRow {
+	rows := make([]cgroupRow, 0, len(cgroups))
+	for _, cg := range cgroups {
+		if cg.Path == "/" {
+			continue
+		}
+		rows = append(rows, cgroupRow{CgroupStats: cg, name: cg.Path})
+	}
+	return rows
+}
+
+// sourceMessage explains why a table fed by the named collector might be empty
+func sourceMessage(metric metrics.Metrics, name string) string {
+	status, ok := metric.Source(name)
+	if !ok {
+		return "Loading..."
+	}
+	if status.Err != nil {
+		return status.Err.Error()
+	}
+	return "Nothing to show"
+}
@@ -0,0 +1,84 @@
+package dashboard
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+var connectionColumns = []tableColumn[metrics.Connection]{
+	{"PROTO", tview.AlignLeft,
+		func(c metrics.Connection) string { return c.Protocol },
+		func(a, b metrics.Connection) bool { return a.Protocol < b.Protocol }},
+	{"LOCAL", tview.AlignLeft,
+		func(c metrics.Connection) string { return formatAddrPort(c.LocalAddr) },
+		func(a, b metrics.Connection) bool { return a.LocalAddr.Compare(b.LocalAddr) < 0 }},
+	{"REMOTE", tview.AlignLeft,
+		func(c metrics.Connection) string { return formatAddrPort(c.RemoteAddr) },
+		func(a, b metrics.Connection) bool { return a.RemoteAddr.Compare(b.RemoteAddr) < 0 }},
+	{"STATE", tview.AlignLeft,
+		func(c metrics.Connection) string { return c.State },
+		func(a, b metrics.Connection) bool { return a.State < b.State }},
+	{"PID", tview.AlignRight,
+		func(c metrics.Connection) string {
+			if c.PID == 0 {
+				return "-"
+			}
+			return fmt.Sprint(c.PID)
+		},
+		func(a, b metrics.Connection) bool { return a.PID < b.PID }},
+	{"COMMAND", tview.AlignLeft,
+		func(c metrics.Connection) string { return c.Command },
+		func(a, b metrics.Connection) bool { return a.Command < b.Command }},
+}
+
+// formatAddrPort shows a socket address, with "*" for the wildcard a listening or unconnected
+// socket has as its remote end
+func formatAddrPort(ap netip.AddrPort) string {
+	if !ap.IsValid() || (ap.Addr().IsUnspecified() && ap.Port() == 0) {
+		return "*"
+	}
+	return ap.String()
+}
+
+// connectionKey identifies a socket across updates so the selection can follow it
+func connectionKey(c metrics.Connection) string {
+	return c.Protocol + " " + c.LocalAddr.String() + " " + c.RemoteAddr.String()
+}
+
+// newConnectionView returns the table of the system's sockets, sorted by state with listeners
+// in green
+func newConnectionView() *sortableTable[metrics.Connection] {
+	t := newSortableTable("Connections", connectionColumns, connectionKey,
+		func(a, b metrics.Connection) bool { return a.LocalAddr.Compare(b.LocalAddr) < 0 })
+	t.sortBy("STATE", false)
+	t.color = func(c metrics.Connection) tcell.Color {
+		if c.State == "LISTEN" {
+			return tcell.ColorGreen
+		}
+		return tcell.ColorDefault
+	}
+	t.caption = func(conns []metrics.Connection) string {
+		return fmt.Sprintf("Connections (%d)  %s", len(conns), renderStateCounts(conns, "  "))
+	}
+	t.shortcuts = map[rune]sortShortcut{
+		's': {"STATE", false}, 'S': {"STATE", false},
+		'p': {"PID", false}, 'P': {"PID", false},
+		'L': {"LOCAL", false}, // not l, which scrolls right
+	}
+	return t
+}
+
+// renderStateCounts summarises sockets by state, e.g. "ESTABLISHED 12  LISTEN 4"
+func renderStateCounts(conns []metrics.Connection, sep string) string {
+	counts := metrics.CountConnectionStates(conns)
+	parts := make([]string, 0, len(counts))
+	for _, c := range counts {
+		parts = append(parts, fmt.Sprintf("%s %d", c.State, c.Count))
+	}
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,74 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+)
+
+var heatmapCells = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// heatColor maps a utilisation percentage to a heatmap colour tag
+func heatColor(value float64) string {
+	switch {
+	case value >= 90:
+		return "red"
+	case value >= 70:
+		return "orange"
+	case value >= 40:
+		return "yellow"
+	case value >= 10:
+		return "green"
+	default:
+		return "darkgreen"
+	}
+}
+
+// renderHeatmapRow draws a history as coloured cells whose height and colour both
+// follow the absolute value, so a pinned core stands out without normalising
+func renderHeatmapRow(history []float64) string {
+	var row strings.Builder
+	for _, point := range history {
+		index := int((point / 100.0) * float64(len(heatmapCells)-1))
+		if index >= len(heatmapCells) {
+			index = len(heatmapCells) - 1
+		} else if index < 0 {
+			index = 0
+		}
+		fmt.Fprintf(&row, "[%s]%c", heatColor(point), heatmapCells[index])
+	}
+	row.WriteString("[-]")
+	return row.String()
+}
+
+// updateCoreHistories records the latest per-core readings, resizing if the core count changes
+func updateCoreHistories(perCore []float64) {
+	if len(coreHistories) != len(perCore) {
+		coreHistories = make([][]float64, len(perCore))
+	}
+	for i, value := range perCore {
+		addPoint(&coreHistories[i], value)
+	}
+}
+
+// renderCores shows one heatmap row per logical CPU, with the busiest core called out on top
+func renderCores(perCore []float64) string {
+	if len(perCore) == 0 {
+		return "[gray]No per-core data[-]"
+	}
+
+	busiest := 0
+	for i, value := range perCore {
+		if value > perCore[busiest] {
+			busiest = i
+		}
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "[yellow]Busiest:[-] cpu%d at [%s]%.1f%%[-]\n", busiest, heatColor(perCore[busiest]), perCore[busiest])
+	mu.Lock()
+	defer mu.Unlock()
+	for i, value := range perCore {
+		fmt.Fprintf(&text, "[yellow]cpu%-3d[-] %s [%s]%5.1f%%[-]\n", i, renderHeatmapRow(coreHistories[i]), heatColor(value), value)
+	}
+	return strings.TrimSuffix(text.String(), "\n")
+}
@@ -0,0 +1,34 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/krisfur/go-resource-monitor/metrics"
+)
+
+// cpuTimeSegments orders the busy CPU time categories for the stacked CPU Time bar; idle is left blank
+func cpuTimeSegments(shares metrics.CPUTimeShares) []barSegment {
+	return []barSegment{
+		{"user", "green", shares.User},
+		{"nice", "teal", shares.Nice},
+		{"sys", "red", shares.System},
+		{"iowait", "yellow", shares.IOWait},
+		{"irq", "purple", shares.IRQ},
+		{"softirq", "fuchsia", shares.SoftIRQ},
+		{"steal", "white", shares.Steal},
+		{"guest", "aqua", shares.Guest},
+	}
+}
+
+// renderLoad shows load averages, the run queue and scheduler rates, each with a sparkline
+func renderLoad(metric metrics.Metrics) string {
+	return fmt.Sprintf(
+		"[yellow]Load:[-] %.2f %.2f %.2f  [yellow]Run:[-] %d  [yellow]Blocked:[-] %d\n[green]%s[-]\n"+
+			"[yellow]Ctx Switches:[-] %.0f/s\n[green]%s[-]\n"+
+			"[yellow]Interrupts:[-] %.0f/s\n[green]%s[-]",
+		metric.Load1, metric.Load5, metric.Load15, metric.ProcsRunning, metric.ProcsBlocked,
+		renderSparkline(normalizeHistory(loadHistory)),
+		metric.ContextSwitchesPerSec, renderSparkline(normalizeHistory(ctxtHistory)),
+		metric.InterruptsPerSec, renderSparkline(normalizeHistory(intrHistory)),
+	)
+}
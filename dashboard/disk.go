@@ -0,0 +1,67 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+// utilColor highlights a device as it approaches saturation
+func utilColor(util float64) string {
+	switch {
+	case util >= 90:
+		return "red"
+	case util >= 60:
+		return "yellow"
+	default:
+		return "-"
+	}
+}
+
+// renderDiskDevices shows an iostat -x style row per whole disk
+func renderDiskDevices(devices []metrics.DiskDeviceStats) string {
+	if len(devices) == 0 {
+		return ""
+	}
+
+	var text strings.Builder
+	text.WriteString("\n[gray]device    rMB/s  wMB/s   r/s   w/s  await  aqu %util[-]")
+	for _, d := range devices {
+		name := d.Name
+		if d.Stacked {
+			name += "*"
+		}
+		fmt.Fprintf(&text, "\n[%s]%-8.8s %6.1f %6.1f %5.0f %5.0f %6.1f %4.1f %5.1f[-]",
+			utilColor(d.UtilPercent), name, d.ReadMBps, d.WriteMBps, d.ReadIOPS, d.WriteIOPS,
+			d.AwaitMs, d.QueueDepth, d.UtilPercent)
+	}
+	for _, d := range devices {
+		if d.Stacked {
+			text.WriteString("\n[gray]* stacked on other disks, not counted in totals[-]")
+			break
+		}
+	}
+	return text.String()
+}
+
+// renderFilesystems shows space and inode usage for each mounted filesystem
+func renderFilesystems(filesystems []metrics.FilesystemStats) string {
+	var text strings.Builder
+	text.WriteString("\n\n[cyan]================================[-]\n[yellow]Filesystems[-]\n[cyan]================================[-]")
+	if len(filesystems) == 0 {
+		text.WriteString("\n[gray]No filesystems[-]")
+	}
+	for _, fs := range filesystems {
+		fmt.Fprintf(&text, "\n[yellow]%s[-] [gray]%s %s[-]\n", tview.Escape(fs.Mountpoint), tview.Escape(fs.Device), tview.Escape(fs.Fstype))
+		fmt.Fprintf(&text, "%s %s / %s\n", renderBar("Space", fs.UsedPercent, 20), formatBytes(fs.Used), formatBytes(fs.Total))
+		if fs.InodesTotal > 0 {
+			text.WriteString(renderBar("Inodes", fs.InodesPercent, 20))
+			fmt.Fprintf(&text, " %d / %d", fs.InodesUsed, fs.InodesTotal)
+		} else {
+			text.WriteString("[yellow]Inodes  [-] [gray]n/a[-]")
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,69 @@
+package dashboard
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// filterPrompt is the "/" line that edits the process filter, shown in place of the footer.
+// The filter is applied as you type; Enter keeps it and Esc puts back the previous one.
+type filterPrompt struct {
+	input    *tview.InputField
+	view     *processView
+	previous string
+	applied  string
+	open     bool
+	onClose  func(status string)
+}
+
+func newFilterPrompt(view *processView, onClose func(status string)) *filterPrompt {
+	f := &filterPrompt{
+		input:   tview.NewInputField(),
+		view:    view,
+		onClose: onClose,
+	}
+	f.input.SetLabel("/")
+	f.input.SetLabelColor(tcell.ColorYellow)
+	f.input.SetFieldBackgroundColor(tcell.ColorDefault)
+	f.input.SetPlaceholder(`e.g. java && cpu>20 || user=postgres || rss>1G`)
+	f.input.SetPlaceholderTextColor(tcell.ColorGray)
+	f.input.SetChangedFunc(func(text string) {
+		// Half-typed expressions are often invalid; keep showing the last good filter meanwhile
+		if err := f.view.setFilter(text); err != nil {
+			f.input.SetFieldTextColor(tcell.ColorRed)
+			return
+		}
+		f.applied = text
+		f.input.SetFieldTextColor(tcell.ColorWhite)
+	})
+	f.input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			if err := f.view.setFilter(f.input.GetText()); err != nil {
+				f.close("[red]" + tview.Escape(err.Error()) + "[-]")
+				f.view.setFilter(f.applied)
+				return
+			}
+			f.applied = f.input.GetText()
+			f.close("")
+		case tcell.KeyEscape:
+			f.view.setFilter(f.previous)
+			f.applied = f.previous
+			f.input.SetText(f.previous)
+			f.close("")
+		}
+	})
+	return f
+}
+
+// start opens the prompt holding the current filter text
+func (f *filterPrompt) start() {
+	f.previous = f.applied
+	f.input.SetText(f.applied)
+	f.open = true
+}
+
+func (f *filterPrompt) close(status string) {
+	f.open = false
+	f.onClose(status)
+}
@@ -0,0 +1,39 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/krisfur/go-resource-monitor/metrics"
+)
+
+// renderHealth lists failing collectors with their error, then unavailable and healthy ones by name
+func renderHealth(sources []metrics.SourceStatus) string {
+	var failing, unavailable, healthy []string
+	for _, s := range sources {
+		switch {
+		case s.OK():
+			healthy = append(healthy, s.Name)
+		case s.Unavailable():
+			reason := strings.TrimPrefix(s.Err.Error(), metrics.ErrUnavailable.Error()+": ")
+			unavailable = append(unavailable, fmt.Sprintf("%s (%s)", s.Name, reason))
+		default:
+			failing = append(failing, fmt.Sprintf("[red]✗ %s:[-] %v", s.Name, s.Err))
+		}
+	}
+
+	var text strings.Builder
+	if len(failing) == 0 {
+		text.WriteString("[green]All sources healthy[-]\n")
+	}
+	for _, line := range failing {
+		text.WriteString(line + "\n")
+	}
+	if len(healthy) > 0 {
+		text.WriteString("[green]✓ OK:[-] " + strings.Join(healthy, ", ") + "\n")
+	}
+	if len(unavailable) > 0 {
+		text.WriteString("[gray]- N/A:[-] " + strings.Join(unavailable, ", ") + "\n")
+	}
+	return strings.TrimSuffix(text.String(), "\n")
+}
@@ -0,0 +1,160 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+// maxClosedListeners caps how many closed listeners stay listed, so services that keep
+// restarting on new ports can't grow the table without bound
+const maxClosedListeners = 100
+
+// listenerEntry is a listening socket seen since the monitor started
+type listenerEntry struct {
+	conn   metrics.Connection
+	isNew  bool      // opened after the first sample
+	opened time.Time // when it was first seen, for new listeners
+	closed time.Time // zero while it is still listening
+}
+
+// listenerRow is a listener as the table shows it
+type listenerRow struct {
+	key string
+	*listenerEntry
+}
+
+// change describes what happened to a listener since the first sample, and when
+func (r listenerRow) change() (string, time.Time) {
+	switch {
+	case !r.closed.IsZero():
+		return "closed " + r.closed.Format("15:04:05"), r.closed
+	case r.isNew:
+		return "opened " + r.opened.Format("15:04:05"), r.opened
+	}
+	return "", time.Time{}
+}
+
+var listenerColumns = []tableColumn[listenerRow]{
+	{"PROTO", tview.AlignLeft,
+		func(r listenerRow) string { return r.conn.Protocol },
+		func(a, b listenerRow) bool { return a.conn.Protocol < b.conn.Protocol }},
+	{"ADDRESS", tview.AlignLeft,
+		func(r listenerRow) string { return r.conn.LocalAddr.Addr().String() },
+		func(a, b listenerRow) bool { return a.conn.LocalAddr.Addr().Less(b.conn.LocalAddr.Addr()) }},
+	{"PORT", tview.AlignRight,
+		func(r listenerRow) string { return fmt.Sprint(r.conn.LocalAddr.Port()) },
+		func(a, b listenerRow) bool { return a.conn.LocalAddr.Port() < b.conn.LocalAddr.Port() }},
+	{"PID", tview.AlignRight,
+		func(r listenerRow) string {
+			if r.conn.PID == 0 {
+				return "-"
+			}
+			return fmt.Sprint(r.conn.PID)
+		},
+		func(a, b listenerRow) bool { return a.conn.PID < b.conn.PID }},
+	{"COMMAND", tview.AlignLeft,
+		func(r listenerRow) string { return r.conn.Command },
+		func(a, b listenerRow) bool { return a.conn.Command < b.conn.Command }},
+	{"CHANGE", tview.AlignLeft,
+		func(r listenerRow) string { text, _ := r.change(); return text },
+		func(a, b listenerRow) bool {
+			_, at := a.change()
+			_, bt := b.change()
+			return at.Before(bt)
+		}},
+}
+
+// listenerView lists listening sockets, marking those opened or closed since the first sample.
+// It is only touched from the UI goroutine.
+type listenerView struct {
+	*sortableTable[listenerRow]
+	entries map[string]*listenerEntry
+	started bool
+}
+
+func newListenerView() *listenerView {
+	t := newSortableTable("Listening", listenerColumns,
+		func(r listenerRow) string { return r.key },
+		func(a, b listenerRow) bool { return a.key < b.key })
+	t.sortBy("PORT", false)
+	t.color = func(r listenerRow) tcell.Color {
+		switch {
+		case !r.closed.IsZero():
+			return tcell.ColorRed
+		case r.isNew:
+			return tcell.ColorGreen
+		}
+		return tcell.ColorDefault
+	}
+	t.caption = func(rows []listenerRow) string {
+		opened, closed := 0, 0
+		for _, r := range rows {
+			switch {
+			case !r.closed.IsZero():
+				closed++
+			case r.isNew:
+				opened++
+			}
+		}
+		return fmt.Sprintf("Listening (%d)  [green]%d opened[-]  [red]%d closed[-] since start", len(rows)-closed, opened, closed)
+	}
+	t.shortcuts = map[rune]sortShortcut{
+		'p': {"PID", false}, 'P': {"PID", false},
+		'L': {"PORT", false}, // not l, which scrolls right
+	}
+	return &listenerView{sortableTable: t, entries: make(map[string]*listenerEntry)}
+}
+
+// update folds in the current listeners and redraws; the first call sets the baseline that
+// later listeners count as new against
+func (v *listenerView) update(conns []metrics.Connection, message string, ok bool) {
+	// A failed read says nothing about which listeners went away
+	if ok {
+		v.merge(conns)
+	}
+	rows := make([]listenerRow, 0, len(v.entries))
+	for key, e := range v.entries {
+		rows = append(rows, listenerRow{key: key, listenerEntry: e})
+	}
+	v.sortableTable.update(rows, message)
+}
+
+// merge marks listeners that are new, gone or back since the last sample
+func (v *listenerView) merge(conns []metrics.Connection) {
+	now := time.Now()
+	current := make(map[string]bool)
+	for _, conn := range metrics.Listeners(conns) {
+		key := conn.Protocol + " " + conn.LocalAddr.String()
+		current[key] = true
+		if e, seen := v.entries[key]; seen {
+			e.conn = conn
+			if !e.closed.IsZero() {
+				// Reopened, e.g. by a restarted service
+				e.closed, e.isNew, e.opened = time.Time{}, true, now
+			}
+			continue
+		}
+		v.entries[key] = &listenerEntry{conn: conn, isNew: v.started, opened: now}
+	}
+	var closed []string
+	for key, e := range v.entries {
+		if !current[key] {
+			if e.closed.IsZero() {
+				e.closed = now
+			}
+			closed = append(closed, key)
+		}
+	}
+	if len(closed) > maxClosedListeners {
+		sort.Slice(closed, func(i, j int) bool { return v.entries[closed[i]].closed.Before(v.entries[closed[j]].closed) })
+		for _, key := range closed[:len(closed)-maxClosedListeners] {
+			delete(v.entries, key)
+		}
+	}
+	v.started = true
+}
@@ -0,0 +1,72 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+// memorySegments splits used RAM into parts that add up to total minus free
+func memorySegments(metric metrics.Metrics) []barSegment {
+	b := metric.MemoryBreakdown
+	if metric.MemoryTotal == 0 {
+		return nil
+	}
+	apps := b.Used
+	if apps >= b.SlabUnreclaimable {
+		apps -= b.SlabUnreclaimable
+	}
+	percent := func(v uint64) float64 {
+		return float64(v) / float64(metric.MemoryTotal) * 100
+	}
+	return []barSegment{
+		{"apps", "green", percent(apps)},
+		{"slab", "red", percent(b.SlabUnreclaimable)},
+		{"shmem", "purple", percent(b.Shared)},
+		{"buffers", "blue", percent(b.Buffers)},
+		{"cache", "teal", percent(b.Cached)},
+		{"slab-rec", "orange", percent(b.SlabReclaimable)},
+	}
+}
+
+// renderMemoryBreakdown shows a segmented memory bar followed by the kernel's detailed counters
+func renderMemoryBreakdown(metric metrics.Metrics) string {
+	b := metric.MemoryBreakdown
+	segments := memorySegments(metric)
+
+	var text strings.Builder
+	text.WriteString(renderStackedBar("RAM", segments, 20) + "\n")
+	text.WriteString(renderLegend(segments) + "\n")
+	fmt.Fprintf(&text, "[yellow]Buffers:[-] %s  [yellow]Shared/tmpfs:[-] %s\n", formatBytes(b.Buffers), formatBytes(b.Shared))
+	fmt.Fprintf(&text, "[yellow]Slab:[-] %s reclaimable, %s unreclaimable\n", formatBytes(b.SlabReclaimable), formatBytes(b.SlabUnreclaimable))
+	fmt.Fprintf(&text, "[yellow]Dirty:[-] %s  [yellow]Writeback:[-] %s\n", formatBytes(b.Dirty), formatBytes(b.Writeback))
+	fmt.Fprintf(&text, "[yellow]Anon:[-] %s  [yellow]File-backed:[-] %s\n", formatBytes(b.Anon), formatBytes(b.File))
+	fmt.Fprintf(&text, "[yellow]Huge Pages:[-] %d/%d free (%s each), THP %s\n",
+		b.HugePagesFree, b.HugePagesTotal, formatBytes(b.HugePageSize), formatBytes(b.AnonHugePages))
+
+	commitColor := "green"
+	if b.CommitLimit > 0 && b.CommittedAS > b.CommitLimit {
+		commitColor = "red"
+	}
+	fmt.Fprintf(&text, "[yellow]Committed:[-] [%s]%s[-] of %s limit", commitColor, formatBytes(b.CommittedAS), formatBytes(b.CommitLimit))
+	return text.String()
+}
+
+// renderSwap shows swap usage, each swap device, and paging rates with sparklines
+func renderSwap(metric metrics.Metrics) string {
+	var text strings.Builder
+	text.WriteString(renderBar("Swap", metric.SwapUsage, 20) + "\n")
+	fmt.Fprintf(&text, "[green]%s[-]\n", renderSparkline(normalizeHistory(swapHistory)))
+	fmt.Fprintf(&text, "[yellow]Swap Used:[-] %.1f / %.1f GB\n",
+		float64(metric.SwapUsed)/1024/1024/1024, float64(metric.SwapTotal)/1024/1024/1024)
+	for _, d := range metric.SwapDevices {
+		fmt.Fprintf(&text, "  [gray]%s:[-] %.1f / %.1f GB\n", tview.Escape(d.Name),
+			float64(d.Used)/1024/1024/1024, float64(d.Total)/1024/1024/1024)
+	}
+	fmt.Fprintf(&text, "[yellow]Swap In:[-] %.2f MB/s  [green]%s[-]\n", metric.SwapInMBps, renderSparkline(normalizeHistory(swapInHistory)))
+	fmt.Fprintf(&text, "[yellow]Swap Out:[-] %.2f MB/s  [green]%s[-]\n", metric.SwapOutMBps, renderSparkline(normalizeHistory(swapOutHistory)))
+	fmt.Fprintf(&text, "[yellow]Major Faults:[-] %.0f/s  [green]%s[-]", metric.MajorFaultsPerSec, renderSparkline(normalizeHistory(majFaultHistory)))
+	return text.String()
+}
@@ -0,0 +1,143 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/krisfur/go-resource-monitor/metrics"
+)
+
+// networkView holds the per-interface sparkline histories and which interface Network Stats
+// shows. The metrics loop feeds and renders it while key presses change the selection, so its
+// state is locked.
+type networkView struct {
+	mu          sync.Mutex
+	sentHistory map[string][]float64
+	recvHistory map[string][]float64
+	names       []string
+	selected    string // empty means all interfaces combined
+}
+
+func newNetworkView() *networkView {
+	return &networkView{
+		sentHistory: make(map[string][]float64),
+		recvHistory: make(map[string][]float64),
+	}
+}
+
+// update records per-interface rates, dropping the history of interfaces that have gone away,
+// such as the veth pairs of stopped containers
+func (v *networkView) update(interfaces []metrics.NetInterfaceStats) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	names := make([]string, 0, len(interfaces))
+	present := make(map[string]bool, len(interfaces))
+	for _, iface := range interfaces {
+		sent := v.sentHistory[iface.Name]
+		recv := v.recvHistory[iface.Name]
+		addPoint(&sent, iface.SentMBps)
+		addPoint(&recv, iface.RecvMBps)
+		v.sentHistory[iface.Name] = sent
+		v.recvHistory[iface.Name] = recv
+		names = append(names, iface.Name)
+		present[iface.Name] = true
+	}
+	for name := range v.sentHistory {
+		if !present[name] {
+			delete(v.sentHistory, name)
+			delete(v.recvHistory, name)
+		}
+	}
+	v.names = names
+}
+
+// cycle moves the Network Stats sparklines to the next interface, wrapping back to all
+func (v *networkView) cycle() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	choices := append([]string{""}, v.names...)
+	next := 0
+	for i, name := range choices {
+		if name == v.selected {
+			next = (i + 1) % len(choices)
+			break
+		}
+	}
+	v.selected = choices[next]
+}
+
+// render shows the sparklines for the selected interface (or all) and a per-interface table
+func (v *networkView) render(metric metrics.Metrics) string {
+	label := "all interfaces"
+	sentMBps, recvMBps := metric.NetSentMBps, metric.NetRecvMBps
+	sentHistory, recvHistory := netSentHistory, netRecvHistory
+
+	v.mu.Lock()
+	if name := v.selected; name != "" {
+		label = name
+		sentMBps, recvMBps = 0, 0
+		for _, iface := range metric.NetInterfaces {
+			if iface.Name == name {
+				sentMBps, recvMBps = iface.SentMBps, iface.RecvMBps
+			}
+		}
+		sentHistory, recvHistory = v.sentHistory[name], v.recvHistory[name]
+	}
+	v.mu.Unlock()
+
+	var text strings.Builder
+	text.WriteString("[cyan]================================[-]\n[yellow]Network Stats[-]\n[cyan]================================[-]\n")
+	fmt.Fprintf(&text, "[yellow]Showing:[-] %s [gray](N to cycle)[-]\n", label)
+	fmt.Fprintf(&text, "[green]Sent MBps:[-] %.2f MB/s\n[green]%s[-]\n", sentMBps, renderSparkline(normalizeHistory(sentHistory)))
+	fmt.Fprintf(&text, "[blue]Recv MBps:[-] %.2f MB/s\n[blue]%s[-]\n", recvMBps, renderSparkline(normalizeHistory(recvHistory)))
+	if len(metric.Connections) > 0 {
+		fmt.Fprintf(&text, "[yellow]Sockets:[-] %s [gray](6 to list)[-]\n", renderStateCounts(metric.Connections, "  "))
+	}
+
+	if len(metric.NetInterfaces) > 0 {
+		text.WriteString("[gray]iface       rx MB  tx MB  rx pk  tx pk  err drop[-]\n")
+		for _, iface := range metric.NetInterfaces {
+			color := "-"
+			if iface.ErrorsPerSec > 0 || iface.DropsPerSec > 0 {
+				color = "red"
+			}
+			fmt.Fprintf(&text, "[%s]%-10.10s %6.2f %6.2f %6.0f %6.0f %4d %4d[-]\n",
+				color, iface.Name, iface.RecvMBps, iface.SentMBps,
+				iface.PacketsRecvPerSec, iface.PacketsSentPerSec,
+				iface.ErrIn+iface.ErrOut, iface.DropIn+iface.DropOut)
+		}
+	}
+	text.WriteString(renderNetHealth(metric))
+	return strings.TrimSuffix(text.String(), "\n")
+}
+
+// renderNetHealth shows the kernel's trouble counters, red when they are climbing, and socket
+// usage; it is empty where the counters can't be read
+func renderNetHealth(metric metrics.Metrics) string {
+	if status, ok := metric.Source("nethealth"); !ok || !status.OK() {
+		return ""
+	}
+	h := metric.NetHealth
+	counter := func(label string, perSec float64) string {
+		color := "-"
+		if perSec > 0 {
+			color = "red"
+		}
+		return fmt.Sprintf("[%s]%s%.1f/s[-]", color, label, perSec)
+	}
+
+	var text strings.Builder
+	// Some retransmission is normal; it only matters once it's a noticeable share of traffic
+	retransColor := "-"
+	if h.TCPRetransPercent >= 1 {
+		retransColor = "red"
+	}
+	fmt.Fprintf(&text, "[yellow]TCP retrans:[-] [%s]%.1f/s (%.2f%%)[-]\n", retransColor, h.TCPRetransPerSec, h.TCPRetransPercent)
+	fmt.Fprintf(&text, "[yellow]TCP RSTs:[-] %s  %s\n", counter("sent ", h.TCPOutRstsPerSec), counter("conn resets ", h.TCPEstabResetsPerSec))
+	fmt.Fprintf(&text, "[yellow]Listen:[-] %s  %s\n", counter("overflows ", h.ListenOverflowsPerSec), counter("drops ", h.ListenDropsPerSec))
+	fmt.Fprintf(&text, "[yellow]UDP:[-] %s  %s\n", counter("rcv errors ", h.UDPInErrorsPerSec), counter("buf errors ", h.UDPRcvbufErrorsPerSec))
+	fmt.Fprintf(&text, "[yellow]Sockets used:[-] %d  TCP %d  UDP %d  orphans %d\n", h.SocketsUsed, h.TCPInUse, h.UDPInUse, h.TCPOrphans)
+	fmt.Fprintf(&text, "[yellow]TIME_WAIT:[-] %d  [yellow]Memory:[-] TCP %s  UDP %s\n", h.TCPTimeWait, formatBytes(h.TCPMemory), formatBytes(h.UDPMemory))
+	return text.String()
+}
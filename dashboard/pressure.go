@@ -0,0 +1,45 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/krisfur/go-resource-monitor/metrics"
+)
+
+// updatePressureHistories tracks the "some" stall share of each resource
+func updatePressureHistories(pressure []metrics.PressureStats) {
+	for _, p := range pressure {
+		history := pressureHistories[p.Resource]
+		addPoint(&history, p.Some.StallPercent)
+		pressureHistories[p.Resource] = history
+	}
+}
+
+// renderPressure shows PSI averages per resource with a sparkline of recent stall time
+func renderPressure(metric metrics.Metrics) string {
+	var text strings.Builder
+	text.WriteString("\n\n[cyan]================================[-]\n[yellow]Pressure (PSI)[-]\n[cyan]================================[-]\n")
+
+	if len(metric.Pressure) == 0 {
+		reason := "no data yet"
+		if status, ok := metric.Source("pressure"); ok && !status.OK() {
+			reason = status.Err.Error()
+		}
+		fmt.Fprintf(&text, "[gray]%s[-]", reason)
+		return text.String()
+	}
+
+	text.WriteString("[gray]             avg10  avg60 avg300[-]\n")
+	for i, p := range metric.Pressure {
+		if i > 0 {
+			text.WriteString("\n")
+		}
+		fmt.Fprintf(&text, "[yellow]%-6s[-] some %5.1f%% %5.1f%% %5.1f%%\n", p.Resource, p.Some.Avg10, p.Some.Avg60, p.Some.Avg300)
+		if p.HasFull {
+			fmt.Fprintf(&text, "       full %5.1f%% %5.1f%% %5.1f%%\n", p.Full.Avg10, p.Full.Avg60, p.Full.Avg300)
+		}
+		fmt.Fprintf(&text, "[green]%s[-] %.1f%% stalled", renderSparkline(normalizeHistory(pressureHistories[p.Resource])), p.Some.StallPercent)
+	}
+	return text.String()
+}
@@ -0,0 +1,201 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+// processDetailView is the page showing everything about one process. The metrics loop
+// refreshes it every tick while it's open, off the UI goroutine, so its state is locked. The
+// inspector is only used by that loop, and outside the lock so slow /proc reads don't hold up
+// the keys.
+type processDetailView struct {
+	text      *tview.TextView
+	inspector *metrics.ProcessInspector
+
+	mu     sync.Mutex
+	pid    int32 // 0 while closed
+	detail metrics.ProcessDetail
+}
+
+func newProcessDetailView() *processDetailView {
+	v := &processDetailView{
+		text:      tview.NewTextView(),
+		inspector: metrics.NewProcessInspector(),
+	}
+	v.text.SetDynamicColors(true)
+	v.text.SetBorder(true)
+	v.text.SetTitle("Process Detail")
+	return v
+}
+
+// open starts showing a process from the next tick
+func (v *processDetailView) open(pid int32) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pid = pid
+	v.detail = metrics.ProcessDetail{}
+	v.text.SetText("Loading...")
+	v.text.ScrollToBeginning()
+}
+
+// close stops the refreshes
+func (v *processDetailView) close() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.pid = 0
+}
+
+// selected returns the process on show, if any
+func (v *processDetailView) selected() (metrics.ProcessInfo, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.detail.ProcessInfo, v.pid != 0 && v.detail.PID == v.pid
+}
+
+// refresh reads the open process again and returns it with the text to show, or false when
+// the pane is closed or moved to another process while it was being read
+func (v *processDetailView) refresh() (int32, string, bool) {
+	v.mu.Lock()
+	pid := v.pid
+	v.mu.Unlock()
+	if pid == 0 {
+		return 0, "", false
+	}
+
+	detail, err := v.inspector.Inspect(pid)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.pid != pid {
+		return 0, "", false
+	}
+	if err != nil {
+		return pid, fmt.Sprintf("[red]Process %d has exited[-]\n\n[gray]Esc to go back[-]", pid), true
+	}
+	v.detail = detail
+	return pid, renderProcessDetail(detail), true
+}
+
+// setText shows text refreshed for pid unless the pane has since been closed or opened on
+// another process; call it on the UI goroutine
+func (v *processDetailView) setText(pid int32, text string) {
+	v.mu.Lock()
+	current := v.pid == pid
+	v.mu.Unlock()
+	if current {
+		v.text.SetText(text)
+	}
+}
+
+func detailHeader(title string) string {
+	return "\n\n[cyan]================================[-]\n[yellow]" + title + "[-]\n[cyan]================================[-]"
+}
+
+// sectionError explains why a section is empty, if it is
+func sectionError(d metrics.ProcessDetail, section string) string {
+	err, ok := d.Errors[section]
+	if !ok {
+		return ""
+	}
+	if errors.Is(err, metrics.ErrUnavailable) {
+		return "\n[gray]" + tview.Escape(err.Error()) + "[-]"
+	}
+	return "\n[red]" + tview.Escape(err.Error()) + "[-]"
+}
+
+// quoteArgs joins a command line, quoting arguments that contain spaces
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
+
+func formatLimit(value uint64, unit string) string {
+	if value == metrics.Unlimited {
+		return "unlimited"
+	}
+	if unit == "bytes" {
+		return formatBytes(value)
+	}
+	return strconv.FormatUint(value, 10)
+}
+
+func renderProcessDetail(d metrics.ProcessDetail) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "[yellow]PID %d[-] %s  [gray]Esc back  K signal  I renice  O ionice[-]\n", d.PID, tview.Escape(d.Name))
+	fmt.Fprintf(&text, "[yellow]User:[-] %s  [yellow]PPID:[-] %d  [yellow]State:[-] %s  [yellow]Nice:[-] %d  [yellow]Started:[-] %s\n",
+		tview.Escape(d.User), d.PPID, d.State, d.Nice, d.StartTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&text, "[yellow]RSS:[-] %s  [yellow]Virtual:[-] %s  [yellow]Threads:[-] %d  [yellow]FDs:[-] %d\n",
+		formatBytes(d.RSS), formatBytes(d.VMS), len(d.Threads), len(d.FDs))
+	if d.Cgroup != "" {
+		fmt.Fprintf(&text, "[yellow]Cgroup:[-] %s\n", tview.Escape(d.Cgroup))
+	}
+	fmt.Fprintf(&text, "[yellow]Command:[-] %s%s\n", tview.Escape(quoteArgs(d.Args)), sectionError(d, "args"))
+	fmt.Fprintf(&text, "[yellow]Executable:[-] %s%s\n", tview.Escape(d.Exe), sectionError(d, "exe"))
+	fmt.Fprintf(&text, "[yellow]Working dir:[-] %s%s", tview.Escape(d.Cwd), sectionError(d, "cwd"))
+
+	text.WriteString(detailHeader("I/O"))
+	if errText := sectionError(d, "io"); errText != "" {
+		text.WriteString(errText)
+	} else {
+		fmt.Fprintf(&text, "\n[yellow]Read: [-] %s/s  [gray](%s total)[-]", formatBytes(uint64(d.ReadBytesPerSec)), formatBytes(d.ReadBytes))
+		fmt.Fprintf(&text, "\n[yellow]Write:[-] %s/s  [gray](%s total)[-]", formatBytes(uint64(d.WriteBytesPerSec)), formatBytes(d.WriteBytes))
+	}
+	fmt.Fprintf(&text, "\n[yellow]Context switches:[-] %d voluntary, %d involuntary", d.VoluntarySwitches, d.InvoluntarySwitches)
+
+	text.WriteString(detailHeader("Memory Map"))
+	text.WriteString(sectionError(d, "memory"))
+	if len(d.Memory) > 0 {
+		text.WriteString("\n[gray]kind    maps       size        rss        pss       swap[-]")
+		for _, m := range d.Memory {
+			fmt.Fprintf(&text, "\n%-6s %5d %10s %10s %10s %10s", m.Kind, m.Count,
+				formatBytes(m.Size), formatBytes(m.RSS), formatBytes(m.PSS), formatBytes(m.Swap))
+		}
+	}
+
+	text.WriteString(detailHeader(fmt.Sprintf("Threads (%d)", len(d.Threads))))
+	text.WriteString(sectionError(d, "threads"))
+	if len(d.Threads) > 0 {
+		threads := append([]metrics.ThreadInfo(nil), d.Threads...)
+		sort.SliceStable(threads, func(i, j int) bool { return threads[i].CPUPercent > threads[j].CPUPercent })
+		text.WriteString("\n[gray]    TID S   CPU%     TIME NAME[-]")
+		for _, t := range threads {
+			fmt.Fprintf(&text, "\n%7d %s %6.1f %8.1f %s", t.TID, t.State, t.CPUPercent, t.CPUSeconds, tview.Escape(t.Name))
+		}
+	}
+
+	text.WriteString(detailHeader(fmt.Sprintf("Open Files (%d)", len(d.FDs))))
+	text.WriteString(sectionError(d, "fds"))
+	for _, f := range d.FDs {
+		fmt.Fprintf(&text, "\n%5d %s", f.FD, tview.Escape(f.Target))
+	}
+
+	text.WriteString(detailHeader("Limits"))
+	text.WriteString(sectionError(d, "limits"))
+	if len(d.Limits) > 0 {
+		text.WriteString("\n[gray]limit                         soft         hard  units[-]")
+		for _, l := range d.Limits {
+			fmt.Fprintf(&text, "\n%-22s %12s %12s  %s", l.Name, formatLimit(l.Soft, l.Unit), formatLimit(l.Hard, l.Unit), l.Unit)
+		}
+	}
+
+	text.WriteString(detailHeader(fmt.Sprintf("Environment (%d)", len(d.Environ))))
+	text.WriteString(sectionError(d, "environ"))
+	for _, env := range d.Environ {
+		text.WriteString("\n" + tview.Escape(env))
+	}
+	return text.String()
+}
@@ -0,0 +1,337 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+// processColumn describes one column of the process table and how to sort by it
+type processColumn = tableColumn[processRow]
+
+// processRow is a process as shown in the table. In tree view it carries the branch
+// drawing for its command and the totals of its subtree.
+type processRow struct {
+	metrics.ProcessInfo
+	prefix      string
+	subtreeCPU  float64
+	subtreeRSS  uint64
+	hasChildren bool
+	collapsed   bool
+}
+
+var processColumns = []processColumn{
+	{"PID", tview.AlignRight,
+		func(p processRow) string { return fmt.Sprint(p.PID) },
+		func(a, b processRow) bool { return a.PID < b.PID }},
+	{"USER", tview.AlignLeft,
+		func(p processRow) string { return p.User },
+		func(a, b processRow) bool { return a.User < b.User }},
+	{"S", tview.AlignLeft,
+		func(p processRow) string { return stateLetter(p.State) },
+		func(a, b processRow) bool { return a.State < b.State }},
+	{"NI", tview.AlignRight,
+		func(p processRow) string { return fmt.Sprint(p.Nice) },
+		func(a, b processRow) bool { return a.Nice < b.Nice }},
+	{"CPU%", tview.AlignRight,
+		func(p processRow) string { return fmt.Sprintf("%.1f", p.CPUPercent) },
+		func(a, b processRow) bool { return a.CPUPercent < b.CPUPercent }},
+	{"RSS", tview.AlignRight,
+		func(p processRow) string { return formatBytes(p.RSS) },
+		func(a, b processRow) bool { return a.RSS < b.RSS }},
+	{"VIRT", tview.AlignRight,
+		func(p processRow) string { return formatBytes(p.VMS) },
+		func(a, b processRow) bool { return a.VMS < b.VMS }},
+	{"THR", tview.AlignRight,
+		func(p processRow) string { return fmt.Sprint(p.Threads) },
+		func(a, b processRow) bool { return a.Threads < b.Threads }},
+	{"START", tview.AlignRight,
+		func(p processRow) string { return formatStartTime(p.StartTime) },
+		func(a, b processRow) bool { return a.StartTime.Before(b.StartTime) }},
+	{"COMMAND", tview.AlignLeft,
+		func(p processRow) string { return p.prefix + p.Command },
+		func(a, b processRow) bool { return a.Command < b.Command }},
+}
+
+// subtreeColumns are added before COMMAND in tree view
+var subtreeColumns = []processColumn{
+	{"ΣCPU%", tview.AlignRight,
+		func(p processRow) string { return fmt.Sprintf("%.1f", p.subtreeCPU) },
+		func(a, b processRow) bool { return a.subtreeCPU < b.subtreeCPU }},
+	{"ΣRSS", tview.AlignRight,
+		func(p processRow) string { return formatBytes(p.subtreeRSS) },
+		func(a, b processRow) bool { return a.subtreeRSS < b.subtreeRSS }},
+}
+
+// Indexes into processColumns used by the sort shortcuts; CPU% is also the default sort
+const (
+	columnPID = 0
+	columnCPU = 4
+	columnRSS = 5
+)
+
+// stateLetter abbreviates a gopsutil process status the way ps and top do
+func stateLetter(state string) string {
+	switch state {
+	case "running":
+		return "R"
+	case "sleep":
+		return "S"
+	case "blocked":
+		return "D"
+	case "idle":
+		return "I"
+	case "stop":
+		return "T"
+	case "zombie":
+		return "Z"
+	case "":
+		return "?"
+	}
+	return strings.ToUpper(state[:1])
+}
+
+// formatStartTime shows the clock time for processes started today and the date otherwise
+func formatStartTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	now := time.Now()
+	if t.YearDay() == now.YearDay() && t.Year() == now.Year() {
+		return t.Format("15:04")
+	}
+	return t.Format("Jan02")
+}
+
+// processView is the top-like process table, optionally as a tree. It is only touched from
+// the UI goroutine.
+type processView struct {
+	table       *tview.Table
+	processes   []metrics.ProcessInfo
+	sortTitle   string // the sort column, by title since tree view adds columns
+	sortDesc    bool
+	selectedPID int32
+	treeMode    bool
+	collapsed   map[int32]bool
+	filter      *metrics.ProcessFilter
+	actions     *processActions
+}
+
+func newProcessView() *processView {
+	v := &processView{
+		table:     tview.NewTable(),
+		sortTitle: processColumns[columnCPU].title,
+		sortDesc:  true,
+		collapsed: make(map[int32]bool),
+	}
+	v.table.SetBorder(true)
+	v.table.SetFixed(1, 0)
+	v.table.SetSelectable(true, false)
+	v.table.SetSelectionChangedFunc(func(row, column int) {
+		if pid, ok := v.table.GetCell(row, 0).GetReference().(int32); ok {
+			v.selectedPID = pid
+		}
+	})
+	return v
+}
+
+// selected returns the process under the cursor
+func (v *processView) selected() (metrics.ProcessInfo, bool) {
+	for _, p := range v.processes {
+		if p.PID == v.selectedPID {
+			return p, true
+		}
+	}
+	return metrics.ProcessInfo{}, false
+}
+
+// setFilter restricts the view to processes matching expr; an empty expression shows everything
+func (v *processView) setFilter(expr string) error {
+	filter, err := metrics.ParseProcessFilter(expr)
+	if err != nil {
+		return err
+	}
+	v.filter = filter
+	v.render()
+	return nil
+}
+
+// update replaces the process list and redraws the table
+func (v *processView) update(processes []metrics.ProcessInfo) {
+	v.processes = processes
+	v.render()
+}
+
+// columns returns the columns for the current mode
+func (v *processView) columns() []processColumn {
+	if !v.treeMode {
+		return processColumns
+	}
+	last := len(processColumns) - 1
+	columns := append([]processColumn{}, processColumns[:last]...)
+	columns = append(columns, subtreeColumns...)
+	return append(columns, processColumns[last])
+}
+
+// sortIndex finds the sort column among columns, or -1 when this mode doesn't have it
+func (v *processView) sortIndex(columns []processColumn) int {
+	for i, c := range columns {
+		if c.title == v.sortTitle {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortRows orders rows by the current sort column, PID breaking ties
+func (v *processView) sortRows(rows []processRow) {
+	columns := v.columns()
+	sortRows(rows, columns[v.sortIndex(columns)], v.sortDesc, func(a, b processRow) bool { return a.PID < b.PID })
+}
+
+// rows returns the processes to show, flat and sorted or as a tree with sorted siblings
+func (v *processView) rows() []processRow {
+	processes := v.filter.Filter(v.processes)
+	if !v.treeMode {
+		rows := make([]processRow, 0, len(processes))
+		for _, p := range processes {
+			rows = append(rows, processRow{ProcessInfo: p, subtreeCPU: p.CPUPercent, subtreeRSS: p.RSS})
+		}
+		v.sortRows(rows)
+		return rows
+	}
+
+	var rows []processRow
+	var walk func(nodes []*metrics.ProcessNode, indent string, top bool)
+	walk = func(nodes []*metrics.ProcessNode, indent string, top bool) {
+		siblings := make([]processRow, 0, len(nodes))
+		byPID := make(map[int32]*metrics.ProcessNode, len(nodes))
+		for _, n := range nodes {
+			siblings = append(siblings, processRow{
+				ProcessInfo: n.ProcessInfo,
+				subtreeCPU:  n.SubtreeCPU,
+				subtreeRSS:  n.SubtreeRSS,
+				hasChildren: len(n.Children) > 0,
+				collapsed:   v.collapsed[n.PID],
+			})
+			byPID[n.PID] = n
+		}
+		v.sortRows(siblings)
+
+		for i, row := range siblings {
+			last := i == len(siblings)-1
+			branch, childIndent := "├─", indent+"│ "
+			if last {
+				branch, childIndent = "└─", indent+"  "
+			}
+			if top {
+				branch, childIndent = "", ""
+			}
+			marker := " "
+			if row.hasChildren {
+				marker = "-"
+				if row.collapsed {
+					marker = "+"
+				}
+			}
+			row.prefix = indent + branch + marker + " "
+			rows = append(rows, row)
+			if row.hasChildren && !row.collapsed {
+				walk(byPID[row.PID].Children, childIndent, false)
+			}
+		}
+	}
+	walk(metrics.BuildProcessTree(processes), "", true)
+	return rows
+}
+
+func (v *processView) render() {
+	columns := v.columns()
+	rows := v.rows()
+
+	setTableHeader(v.table, columns, v.sortIndex(columns), v.sortDesc)
+
+	selectedRow := 0
+	for i, p := range rows {
+		row := i + 1
+		for col, column := range columns {
+			cell := tview.NewTableCell(tview.Escape(column.value(p))).SetAlign(column.align)
+			if col == 0 {
+				cell.SetReference(p.PID)
+			}
+			if p.State == "running" {
+				cell.SetTextColor(tcell.ColorGreen)
+			}
+			v.table.SetCell(row, col, cell)
+		}
+		if p.PID == v.selectedPID {
+			selectedRow = row
+		}
+	}
+
+	title := "Processes"
+	if v.treeMode {
+		title = "Process Tree"
+	}
+	if v.filter != nil {
+		v.table.SetTitle(fmt.Sprintf("%s (%d of %d matching %s)", title, len(v.filter.Filter(v.processes)), len(v.processes), tview.Escape(v.filter.String())))
+	} else {
+		v.table.SetTitle(fmt.Sprintf("%s (%d)", title, len(v.processes)))
+	}
+	if selectedRow == 0 && len(rows) > 0 {
+		selectedRow = 1
+	}
+	v.table.Select(selectedRow, 0)
+}
+
+// handleKey deals with the sort and tree keys; anything else goes on to the table for scrolling
+func (v *processView) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	columns := v.columns()
+	sortColumn := v.sortIndex(columns)
+	switch event.Rune() {
+	case '<', ',':
+		v.sortTitle = columns[(sortColumn+len(columns)-1)%len(columns)].title
+	case '>', '.':
+		v.sortTitle = columns[(sortColumn+1)%len(columns)].title
+	case 'r', 'R':
+		v.sortDesc = !v.sortDesc
+	case 'c', 'C':
+		v.sortTitle, v.sortDesc = processColumns[columnCPU].title, true
+	case 'm', 'M':
+		v.sortTitle, v.sortDesc = processColumns[columnRSS].title, true
+	case 'p', 'P':
+		v.sortTitle, v.sortDesc = processColumns[columnPID].title, false
+	case 't', 'T':
+		// The sort stays on its column unless that was a subtree total, which flat view lacks
+		v.treeMode = !v.treeMode
+		if v.sortIndex(v.columns()) < 0 {
+			v.sortTitle, v.sortDesc = processColumns[columnCPU].title, true
+		}
+	case ' ':
+		v.collapsed[v.selectedPID] = !v.collapsed[v.selectedPID]
+	case '-':
+		v.collapsed[v.selectedPID] = true
+	case '+', '=':
+		delete(v.collapsed, v.selectedPID)
+	case 'K', 'I', 'O':
+		if p, ok := v.selected(); ok && v.actions != nil {
+			v.actions.handleKey(event, p)
+		}
+		return nil
+	default:
+		return event
+	}
+	v.render()
+	return nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+// tempColor colours a temperature by how close it is to the chip's own thresholds, or to
+// fixed ones when the chip doesn't give any
+func tempColor(t metrics.TempSensor) string {
+	high, critical := t.High, t.Critical
+	if high == 0 {
+		high = 80
+	}
+	if critical == 0 {
+		critical = 95
+	}
+	switch {
+	case t.Celsius >= critical:
+		return "red"
+	case t.Celsius >= high:
+		return "orange"
+	default:
+		return "-"
+	}
+}
+
+// renderSensors lists every chip's readings, marking the one used as CPU Temp; it is empty
+// when there is nothing to show
+func renderSensors(metric metrics.Metrics) string {
+	var text strings.Builder
+	if metric.CPUTempSensor != "" {
+		fmt.Fprintf(&text, "[yellow]CPU Temp:[-] %.1f°C from %s [gray](choose another with -cpu-sensor chip/label)[-]\n",
+			metric.CPUTemp, tview.Escape(metric.CPUTempSensor))
+	} else if len(metric.Sensors) > 0 {
+		text.WriteString("[yellow]CPU Temp:[-] no CPU sensor recognised [gray](pick one with -cpu-sensor chip/label)[-]\n")
+	} else if metric.CPUTemp > 0 {
+		fmt.Fprintf(&text, "[yellow]CPU Temp:[-] %.1f°C\n", metric.CPUTemp)
+	}
+	if len(metric.CoreTemps) > 0 {
+		text.WriteString("[yellow]Cores:[-]")
+		for _, t := range metric.CoreTemps {
+			fmt.Fprintf(&text, "  %s [%s]%.0f°C[-]", tview.Escape(t.Label), tempColor(t), t.Celsius)
+		}
+		text.WriteString("\n")
+	}
+
+	for _, chip := range metric.Sensors {
+		fmt.Fprintf(&text, "\n[cyan]%s[-] [gray](%s)[-]\n", tview.Escape(chip.Name), tview.Escape(chip.ID))
+		for _, t := range chip.Temperatures {
+			fmt.Fprintf(&text, "  %-20s [%s]%7.1f°C[-]", tview.Escape(t.Label), tempColor(t), t.Celsius)
+			if t.High > 0 {
+				fmt.Fprintf(&text, "  high %.1f°C", t.High)
+			}
+			if t.Critical > 0 {
+				fmt.Fprintf(&text, "  crit %.1f°C", t.Critical)
+			}
+			if metrics.SensorName(chip, t.Label) == metric.CPUTempSensor {
+				text.WriteString("  [green]◀ CPU Temp[-]")
+			}
+			text.WriteString("\n")
+		}
+		for _, f := range chip.Fans {
+			// Only a fan below its minimum is a fault; one without a minimum may be stopped
+			// on purpose, like a case fan that only spins under load
+			color := "-"
+			if f.Min > 0 && f.RPM < f.Min {
+				color = "red"
+			} else if f.RPM == 0 {
+				color = "gray"
+			}
+			fmt.Fprintf(&text, "  %-20s [%s]%7.0f RPM[-]", tview.Escape(f.Label), color, f.RPM)
+			if f.Min > 0 {
+				fmt.Fprintf(&text, "  min %.0f RPM", f.Min)
+			}
+			text.WriteString("\n")
+		}
+		for _, v := range chip.Voltages {
+			color := "-"
+			if (v.Min > 0 && v.Volts < v.Min) || (v.Max > 0 && v.Volts > v.Max) {
+				color = "red"
+			}
+			fmt.Fprintf(&text, "  %-20s [%s]%8.3f V[-]", tview.Escape(v.Label), color, v.Volts)
+			if v.Min > 0 || v.Max > 0 {
+				fmt.Fprintf(&text, "  range %.3f–%.3f V", v.Min, v.Max)
+			}
+			text.WriteString("\n")
+		}
+		for _, p := range chip.Power {
+			fmt.Fprintf(&text, "  %-20s %8.2f W\n", tview.Escape(p.Label), p.Watts)
+		}
+	}
+	return strings.TrimSuffix(text.String(), "\n")
+}
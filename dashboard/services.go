@@ -0,0 +1,32 @@
+package dashboard
+
+import (
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+// serviceColumns are the columns of the systemd unit table; rows are named by cgroup path,
+// which unlike the unit name is unique
+var serviceColumns = []cgroupColumn{
+	cgroupCPUColumn,
+	cgroupMemoryColumn,
+	cgroupTasksColumn,
+	cgroupReadColumn,
+	cgroupWriteColumn,
+	{"UNIT", tview.AlignLeft,
+		func(r cgroupRow) string { return r.unit },
+		func(a, b cgroupRow) bool { return a.unit < b.unit }},
+	{"SLICE", tview.AlignLeft,
+		func(r cgroupRow) string { return r.slice },
+		func(a, b cgroupRow) bool { return a.slice < b.slice }},
+}
+
+// serviceRows turns the cgroup walk into one row per systemd unit
+func serviceRows(cgroups []metrics.CgroupStats) []cgroupRow {
+	units := metrics.SystemdUnits(cgroups)
+	rows := make([]cgroupRow, 0, len(units))
+	for _, u := range units {
+		rows = append(rows, cgroupRow{CgroupStats: u.CgroupStats, name: u.Path, unit: u.Unit, slice: u.Slice})
+	}
+	return rows
+}
@@ -0,0 +1,172 @@
+package dashboard
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// tableColumn is one column of a sortable table: how a row shows in it and how to order by it
+type tableColumn[T any] struct {
+	title string
+	align int
+	value func(r T) string
+	less  func(a, b T) bool
+}
+
+// sortShortcut is a key that jumps straight to sorting on a column
+type sortShortcut struct {
+	title string
+	desc  bool
+}
+
+// sortableTable is a table whose rows sort on any column, with the selection following its
+// row across updates. Pages supply the columns and say how rows are told apart. It is only
+// touched from the UI goroutine.
+type sortableTable[T any] struct {
+	table      *tview.Table
+	columns    []tableColumn[T]
+	rows       []T
+	message    string // shown instead of the rows when there are none
+	sortColumn int
+	sortDesc   bool
+	selected   string
+
+	key       func(r T) string      // identifies a row across updates
+	order     func(a, b T) bool     // breaks ties in the sort column
+	color     func(r T) tcell.Color // colours a whole row; nil leaves them all plain
+	caption   func(rows []T) string // the border title
+	shortcuts map[rune]sortShortcut // case matters, so that h/j/k/l still scroll
+}
+
+// newSortableTable returns a table titled "title (rows)", sorted on its first column, largest first
+func newSortableTable[T any](title string, columns []tableColumn[T], key func(r T) string, order func(a, b T) bool) *sortableTable[T] {
+	t := &sortableTable[T]{
+		table:    tview.NewTable(),
+		columns:  columns,
+		sortDesc: true,
+		key:      key,
+		order:    order,
+		caption:  func(rows []T) string { return fmt.Sprintf("%s (%d)", title, len(rows)) },
+	}
+	t.table.SetBorder(true)
+	t.table.SetTitle(title)
+	t.table.SetFixed(1, 0)
+	t.table.SetSelectable(true, false)
+	t.table.SetSelectionChangedFunc(func(row, column int) {
+		if key, ok := t.table.GetCell(row, 0).GetReference().(string); ok {
+			t.selected = key
+		}
+	})
+	return t
+}
+
+// update replaces the rows and redraws; message explains an empty table
+func (t *sortableTable[T]) update(rows []T, message string) {
+	t.rows, t.message = rows, message
+	t.render()
+}
+
+func (t *sortableTable[T]) render() {
+	rows := append([]T(nil), t.rows...)
+	sortRows(rows, t.columns[t.sortColumn], t.sortDesc, t.order)
+
+	setTableHeader(t.table, t.columns, t.sortColumn, t.sortDesc)
+	if len(rows) == 0 && t.message != "" {
+		// In the last column, which stretches, so the other columns keep their width
+		t.table.SetCell(1, len(t.columns)-1, tview.NewTableCell(tview.Escape(t.message)).SetTextColor(tcell.ColorGray).SetSelectable(false))
+	}
+
+	selectedRow := 0
+	for i, r := range rows {
+		key := t.key(r)
+		color := tcell.ColorDefault
+		if t.color != nil {
+			color = t.color(r)
+		}
+		for col, c := range t.columns {
+			cell := tview.NewTableCell(tview.Escape(c.value(r))).SetAlign(c.align)
+			if color != tcell.ColorDefault {
+				cell.SetTextColor(color)
+			}
+			if col == 0 {
+				cell.SetReference(key)
+			}
+			t.table.SetCell(i+1, col, cell)
+		}
+		if key == t.selected {
+			selectedRow = i + 1
+		}
+	}
+
+	t.table.SetTitle(t.caption(rows))
+	if selectedRow == 0 && len(rows) > 0 {
+		selectedRow = 1
+	}
+	t.table.Select(selectedRow, 0)
+}
+
+// handleKey deals with the sort keys; anything else goes on to the table for scrolling
+func (t *sortableTable[T]) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	switch r := event.Rune(); r {
+	case '<', ',':
+		t.sortColumn = (t.sortColumn + len(t.columns) - 1) % len(t.columns)
+	case '>', '.':
+		t.sortColumn = (t.sortColumn + 1) % len(t.columns)
+	case 'r', 'R':
+		t.sortDesc = !t.sortDesc
+	default:
+		shortcut, ok := t.shortcuts[r]
+		if !ok {
+			return event
+		}
+		t.sortBy(shortcut.title, shortcut.desc)
+	}
+	t.render()
+	return nil
+}
+
+// sortBy sorts on the column with the given title
+func (t *sortableTable[T]) sortBy(title string, desc bool) {
+	for i, c := range t.columns {
+		if c.title == title {
+			t.sortColumn, t.sortDesc = i, desc
+		}
+	}
+}
+
+// sortRows orders rows by a column, with order breaking ties the same way in either direction
+func sortRows[T any](rows []T, column tableColumn[T], desc bool, order func(a, b T) bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if column.less(rows[i], rows[j]) {
+			return !desc
+		}
+		if column.less(rows[j], rows[i]) {
+			return desc
+		}
+		return order(rows[i], rows[j])
+	})
+}
+
+// setTableHeader clears a table and writes its header row, marking the sort column with its
+// direction; the last column stretches to fill the width
+func setTableHeader[T any](table *tview.Table, columns []tableColumn[T], sortColumn int, desc bool) {
+	table.Clear()
+	for col, c := range columns {
+		title := c.title
+		if col == sortColumn {
+			if desc {
+				title += "▼"
+			} else {
+				title += "▲"
+			}
+		}
+		table.SetCell(0, col, tview.NewTableCell(title).
+			SetTextColor(tcell.ColorYellow).
+			SetAlign(c.align).
+			SetSelectable(false).
+			SetExpansion(boolToInt(col == len(columns)-1)))
+	}
+}
@@ -2,6 +2,7 @@ package dashboard
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"sync"
 	"time"
@@ -18,20 +19,53 @@ const (
 	sparklinePoints = 30
 )
 
+// Page names, in the order of the number keys that select them
+const (
+	pageOverview    = "overview"
+	pageProcesses   = "processes"
+	pageDetail      = "detail"
+	pageCgroups     = "cgroups"
+	pageServices    = "services"
+	pageUsers       = "users"
+	pageConnections = "connections"
+	pageSensors     = "sensors"
+)
+
+// pageHelp is the footer text shown on each page
+var pageHelp = map[string]string{
+	pageOverview:    "[yellow]Q quit  1 overview  2 processes  3 cgroups  4 services  5 users  6 connections  7 sensors  Tab switch panel  arrows scroll  N cycle network interface",
+	pageProcesses:   "[yellow]Q quit  1 overview  2 processes  3 cgroups  4 services  5 users  6 connections  7 sensors  arrows/PgUp/PgDn scroll  </> sort column  R reverse  C cpu  M memory  P pid  T tree  Space collapse  K signal  I renice  O ionice  / filter  Enter details",
+	pageCgroups:     "[yellow]Q quit  1 overview  2 processes  3 cgroups  4 services  5 users  6 connections  7 sensors  arrows/PgUp/PgDn scroll  </> sort column  R reverse  C cpu  M memory",
+	pageServices:    "[yellow]Q quit  1 overview  2 processes  3 cgroups  4 services  5 users  6 connections  7 sensors  arrows/PgUp/PgDn scroll  </> sort column  R reverse  C cpu  M memory",
+	pageUsers:       "[yellow]Q quit  1 overview  2 processes  3 cgroups  4 services  5 users  6 connections  7 sensors  arrows/PgUp/PgDn scroll  </> sort column  R reverse  C cpu  M memory  P processes  Enter show processes",
+	pageConnections: "[yellow]Q quit  1 overview  2 processes  3 cgroups  4 services  5 users  6 connections  7 sensors  Tab switch panel  arrows/PgUp/PgDn scroll  </> sort column  R reverse  S state  P pid  L local address",
+	pageSensors:     "[yellow]Q quit  1 overview  2 processes  3 cgroups  4 services  5 users  6 connections  7 sensors  arrows/PgUp/PgDn scroll",
+	pageDetail:      "[yellow]Q quit  1 overview  2 processes  3 cgroups  4 services  5 users  6 connections  7 sensors  Esc back  arrows/PgUp/PgDn scroll  K signal  I renice  O ionice",
+}
+
 var (
-	cpuHistory       []float64
-	memHistory       []float64
-	diskHistory      []float64
-	netSentHistory   []float64
-	netRecvHistory   []float64
-	diskReadHistory  []float64
-	diskWriteHistory []float64
-	gpuUtilHistories map[string][]float64
-	mu               sync.Mutex
+	cpuHistory        []float64
+	memHistory        []float64
+	netSentHistory    []float64
+	netRecvHistory    []float64
+	diskReadHistory   []float64
+	diskWriteHistory  []float64
+	gpuUtilHistories  map[string][]float64
+	coreHistories     [][]float64
+	pressureHistories map[string][]float64
+	loadHistory       []float64
+	ctxtHistory       []float64
+	intrHistory       []float64
+	swapHistory       []float64
+	swapInHistory     []float64
+	swapOutHistory    []float64
+	majFaultHistory   []float64
+	mu                sync.Mutex
 )
 
 func init() {
 	gpuUtilHistories = make(map[string][]float64)
+	pressureHistories = make(map[string][]float64)
 }
 
 var gopherFrames = [][]string{
@@ -73,6 +107,88 @@ func renderBar(label string, value float64, barWidth int) string {
 	return fmt.Sprintf("[yellow]%s[-] %s %.1f%%", paddedLabel, bar, value)
 }
 
+// formatBytes renders a byte count with a binary unit suited to its size
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTP"[exp])
+}
+
+// renderContainer is the System Info line about the cgroup the monitor runs in, empty on a
+// host with no limits of its own
+func renderContainer(c metrics.ContainerInfo) string {
+	if !c.Containerised && !c.CPUFromLimit && !c.MemoryFromLimit {
+		return ""
+	}
+	cpuLimit, memoryLimit := "no CPU limit", "no memory limit"
+	if c.CPULimit > 0 {
+		cpuLimit = fmt.Sprintf("%g CPU", math.Round(c.CPULimit*100)/100)
+	}
+	if c.MemoryLimit > 0 {
+		memoryLimit = strings.Replace(formatBytes(c.MemoryLimit), ".0 ", " ", 1)
+	}
+
+	label, state := "Container:", "containerised"
+	if !c.Containerised {
+		label, state = "Cgroup:", tview.Escape(c.Cgroup)
+	} else if c.Runtime != "" {
+		state += " (" + tview.Escape(c.Runtime) + ")"
+	}
+	limits := "no limits"
+	if c.CPULimit > 0 || c.MemoryLimit > 0 {
+		limits = "limits: " + cpuLimit + " / " + memoryLimit
+	}
+	text := fmt.Sprintf("\n[yellow]%s[-] %s, %s", label, state, limits)
+	if c.CPUFromLimit || c.MemoryFromLimit {
+		text += " [gray](% of limits)[-]"
+	}
+	return text
+}
+
+// barSegment is one coloured part of a stacked bar, as a percentage of the whole bar
+type barSegment struct {
+	label string
+	color string
+	value float64
+}
+
+func renderStackedBar(label string, segments []barSegment, barWidth int) string {
+	var bar strings.Builder
+	used := 0
+	for _, seg := range segments {
+		cells := int(math.Round((seg.value / 100.0) * float64(barWidth)))
+		if used+cells > barWidth {
+			cells = barWidth - used
+		}
+		if cells <= 0 {
+			continue
+		}
+		fmt.Fprintf(&bar, "[%s]%s[-]", seg.color, strings.Repeat("█", cells))
+		used += cells
+	}
+	paddedLabel := fmt.Sprintf("%-8s", label)
+	return fmt.Sprintf("[yellow]%s[-] [%s%s]", paddedLabel, bar.String(), strings.Repeat(" ", barWidth-used))
+}
+
+// renderLegend labels the segments of a stacked bar, leaving out any that are too small to see
+func renderLegend(segments []barSegment) string {
+	parts := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		if seg.value < 0.1 {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("[%s]■[-] %s %.1f%%", seg.color, seg.label, seg.value))
+	}
+	return strings.Join(parts, "  ")
+}
+
 func renderSparkline(history []float64) string {
 	bars := []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
 	var sparkline strings.Builder
@@ -117,7 +233,12 @@ func normalizeHistory(history []float64) []float64 {
 	return normalized
 }
 
-func StartUI(metricsChan <-chan metrics.Metrics, quitChan chan<- struct{}) {
+// Options holds the dashboard settings that come from the command line
+type Options struct {
+	ProcessFilter string // initial process filter, in metrics.ParseProcessFilter syntax
+}
+
+func StartUI(metricsChan <-chan metrics.Metrics, quitChan chan<- struct{}, opts Options) {
 	app := tview.NewApplication()
 
 	// Gopher Art Box
@@ -136,6 +257,13 @@ func StartUI(metricsChan <-chan metrics.Metrics, quitChan chan<- struct{}) {
 		}
 	}()
 
+	// Collector Health Box
+	healthBox := tview.NewTextView()
+	healthBox.SetDynamicColors(true)
+	healthBox.SetBorder(true)
+	healthBox.SetTitle("Collector Health")
+	healthBox.SetText("Loading...")
+
 	// System Info Box
 	sysInfoBox := tview.NewTextView()
 	sysInfoBox.SetDynamicColors(true)
@@ -163,41 +291,163 @@ func StartUI(metricsChan <-chan metrics.Metrics, quitChan chan<- struct{}) {
 	})
 	metricsBoxRight.SetTextAlign(tview.AlignCenter)
 	metricsBoxRight.SetText("Loading...")
+	networkView := newNetworkView()
+
+	// CPU Cores Box - per-core heatmap
+	coresBox := tview.NewTextView()
+	coresBox.SetDynamicColors(true)
+	coresBox.SetBorder(true)
+	coresBox.SetTitle("CPU Cores")
+	coresBox.SetText("Loading...")
+
+	// Process Table
+	procView := newProcessView()
+	if err := procView.setFilter(opts.ProcessFilter); err != nil {
+		panic(err)
+	}
 
 	// Footer Box
 	footerBox := tview.NewTextView()
 	footerBox.SetDynamicColors(true)
 	footerBox.SetBorder(false)
-	footerBox.SetText("[yellow]Press Q to quit.")
 
 	// Layout
 	flex := tview.NewFlex().SetDirection(tview.FlexRow)
-	flex.AddItem(gopherBox, 13, 0, false)
+	topFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
+	topFlex.AddItem(gopherBox, 30, 0, false)
+	topFlex.AddItem(healthBox, 0, 1, false)
+	flex.AddItem(topFlex, 13, 0, false)
 	flex.AddItem(sysInfoBox, 7, 0, false)
 
 	// Create horizontal flex for the two columns
 	metricsFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
 	metricsFlex.AddItem(metricsBoxLeft, 0, 1, true)
 	metricsFlex.AddItem(metricsBoxRight, 0, 1, false)
+	metricsFlex.AddItem(coresBox, 0, 1, false)
 
 	flex.AddItem(metricsFlex, 0, 1, true)
-	flex.AddItem(footerBox, 1, 0, false)
 
-	// Populate System Info once
-	go func() {
-		hostInfo, _ := host.Info()
-		cpuInfo, _ := cpu.Info()
+	// Each view is a page; number keys switch between them
+	pages := tview.NewPages()
+	pages.AddPage(pageOverview, flex, true, true)
+	pages.AddPage(pageProcesses, procView.table, true, false)
+	detailView := newProcessDetailView()
+	pages.AddPage(pageDetail, detailView.text, true, false)
+	cgroupView := newCgroupTable("Cgroups", cgroupColumns)
+	pages.AddPage(pageCgroups, cgroupView.table, true, false)
+	serviceView := newCgroupTable("Services", serviceColumns)
+	pages.AddPage(pageServices, serviceView.table, true, false)
+	userView := newUserView()
+	pages.AddPage(pageUsers, userView.table, true, false)
+	// Listening sockets above every connection; Tab moves between the two
+	connectionView := newConnectionView()
+	listenerView := newListenerView()
+	connectionsFlex := tview.NewFlex().SetDirection(tview.FlexRow)
+	connectionsFlex.AddItem(listenerView.table, 0, 1, false)
+	connectionsFlex.AddItem(connectionView.table, 0, 2, true)
+	pages.AddPage(pageConnections, connectionsFlex, true, false)
+	sensorsBox := tview.NewTextView()
+	sensorsBox.SetDynamicColors(true)
+	sensorsBox.SetBorder(true)
+	sensorsBox.SetTitle("Sensors")
+	sensorsBox.SetText("Loading...")
+	pages.AddPage(pageSensors, sensorsBox, true, false)
+
+	root := tview.NewFlex().SetDirection(tview.FlexRow)
+	root.AddItem(pages, 0, 1, true)
+	root.AddItem(footerBox, 1, 0, false)
+
+	currentPage := pageOverview
+	showPage := func(name string, focus tview.Primitive) {
+		currentPage = name
+		pages.SwitchToPage(name)
+		app.SetFocus(focus)
+		footerBox.SetText(pageHelp[name])
+		if name != pageDetail {
+			detailView.close()
+		}
+		if procView.actions != nil {
+			procView.actions.returnTo = focus
+		}
+	}
+	footerBox.SetText(pageHelp[pageOverview])
 
-		sysInfoText := fmt.Sprintf(
-			"[yellow]OS:[-] %s %s\n[yellow]Host:[-] %s\n[yellow]Kernel:[-] %s\n[yellow]CPU:[-] %s (%d cores)",
-			hostInfo.Platform, hostInfo.PlatformVersion,
-			hostInfo.Hostname,
-			hostInfo.KernelVersion,
-			cpuInfo[0].ModelName, len(cpuInfo),
-		)
+	// showStatus puts a one-off message in front of the current page's key help
+	showStatus := func(status string) {
+		if status == "" {
+			footerBox.SetText(pageHelp[currentPage])
+			return
+		}
+		footerBox.SetText(status + "  " + pageHelp[currentPage])
+	}
+
+	procView.actions = &processActions{
+		app:       app,
+		pages:     pages,
+		returnTo:  procView.table,
+		setStatus: showStatus,
+	}
+
+	// Enter on a process opens its detail page, filled in on the next tick
+	procView.table.SetSelectedFunc(func(row, column int) {
+		p, ok := procView.selected()
+		if !ok {
+			return
+		}
+		detailView.open(p.PID)
+		showPage(pageDetail, detailView.text)
+	})
+
+	var filter *filterPrompt
+	filter = newFilterPrompt(procView, func(status string) {
+		root.RemoveItem(filter.input)
+		root.AddItem(footerBox, 1, 0, false)
+		showStatus(status)
+		app.SetFocus(procView.table)
+	})
+	filter.applied = opts.ProcessFilter
+
+	// Enter on a user drills down to their processes, through the process filter so / shows
+	// how to widen it again
+	userView.table.SetSelectedFunc(func(row, column int) {
+		name := userView.selected
+		if name == "" || name == "?" {
+			return
+		}
+		expr := "user=" + name
+		if err := procView.setFilter(expr); err != nil {
+			showStatus("[red]" + tview.Escape(err.Error()) + "[-]")
+			return
+		}
+		filter.applied = expr
+		showPage(pageProcesses, procView.table)
+		showStatus(fmt.Sprintf("[green]Processes of %s[-], / to change the filter", tview.Escape(name)))
+	})
+
+	// System Info is the host details, read once, plus the container line, which comes with
+	// each sample; both are only touched on the UI goroutine
+	var hostInfoText, containerText string
+	go func() {
+		var sysInfoText string
+		if hostInfo, err := host.Info(); err == nil {
+			sysInfoText = fmt.Sprintf(
+				"[yellow]OS:[-] %s %s\n[yellow]Host:[-] %s\n[yellow]Kernel:[-] %s\n",
+				hostInfo.Platform, hostInfo.PlatformVersion,
+				hostInfo.Hostname,
+				hostInfo.KernelVersion,
+			)
+		} else {
+			sysInfoText = fmt.Sprintf("[red]Host info unavailable:[-] %v\n", err)
+		}
+		if cpuInfo, err := cpu.Info(); err == nil && len(cpuInfo) > 0 {
+			sysInfoText += fmt.Sprintf("[yellow]CPU:[-] %s (%d cores)", cpuInfo[0].ModelName, len(cpuInfo))
+		} else {
+			sysInfoText += "[yellow]CPU:[-] unknown"
+		}
 
 		app.QueueUpdateDraw(func() {
-			sysInfoBox.SetText(sysInfoText)
+			hostInfoText = sysInfoText
+			sysInfoBox.SetText(hostInfoText + containerText)
 		})
 	}()
 
@@ -206,11 +456,20 @@ func StartUI(metricsChan <-chan metrics.Metrics, quitChan chan<- struct{}) {
 		for metric := range metricsChan {
 			addPoint(&cpuHistory, metric.CPUUsage)
 			addPoint(&memHistory, metric.MemoryUsage)
-			addPoint(&diskHistory, metric.DiskUsage)
 			addPoint(&netSentHistory, metric.NetSentMBps)
 			addPoint(&netRecvHistory, metric.NetRecvMBps)
 			addPoint(&diskReadHistory, metric.DiskReadMBps)
 			addPoint(&diskWriteHistory, metric.DiskWriteMBps)
+			updateCoreHistories(metric.CPUPerCore)
+			updatePressureHistories(metric.Pressure)
+			networkView.update(metric.NetInterfaces)
+			addPoint(&loadHistory, metric.Load1)
+			addPoint(&ctxtHistory, metric.ContextSwitchesPerSec)
+			addPoint(&intrHistory, metric.InterruptsPerSec)
+			addPoint(&swapHistory, metric.SwapUsage)
+			addPoint(&swapInHistory, metric.SwapInMBps)
+			addPoint(&swapOutHistory, metric.SwapOutMBps)
+			addPoint(&majFaultHistory, metric.MajorFaultsPerSec)
 
 			// Update GPU utilization histories
 			for _, gpu := range metric.GPUs {
@@ -224,9 +483,6 @@ func StartUI(metricsChan <-chan metrics.Metrics, quitChan chan<- struct{}) {
 
 			cpuSpark := renderSparkline(normalizeHistory(cpuHistory))
 			memSpark := renderSparkline(normalizeHistory(memHistory))
-			diskSpark := renderSparkline(normalizeHistory(diskHistory))
-			sentSpark := renderSparkline(normalizeHistory(netSentHistory))
-			recvSpark := renderSparkline(normalizeHistory(netRecvHistory))
 			diskReadSpark := renderSparkline(normalizeHistory(diskReadHistory))
 			diskWriteSpark := renderSparkline(normalizeHistory(diskWriteHistory))
 
@@ -235,22 +491,28 @@ func StartUI(metricsChan <-chan metrics.Metrics, quitChan chan<- struct{}) {
 				cpuTempStr = fmt.Sprintf("%.0f°C", metric.CPUTemp)
 			}
 
+			cpuBreakdown := cpuTimeSegments(metric.CPUBreakdown)
+
 			// Left box content - Main system metrics
 			leftText := fmt.Sprintf(
-				"%s\n[green]%s[-]\n%s\n[green]%s[-]\n\n"+
+				"%s\n[green]%s[-]\n%s\n%s\n%s\n\n"+
 					"[cyan]================================[-]\n[yellow]System Stats[-]\n[cyan]================================[-]\n"+
 					"[yellow]CPU Temp:[-] %s\n"+
 					"[yellow]Battery:[-] %.2f%% (%s)\n"+
 					"[yellow]Uptime:[-] %dd %dh %dm",
 				renderBar("CPU", metric.CPUUsage, 20),
 				cpuSpark,
-				renderBar("Disk", metric.DiskUsage, 20),
-				diskSpark,
+				renderStackedBar("CPU Time", cpuBreakdown, 20),
+				renderLegend(cpuBreakdown),
+				renderLoad(metric),
 				cpuTempStr,
 				metric.BatteryPercent, metric.BatteryState,
 				metric.UptimeDays, metric.UptimeHours, metric.UptimeMinutes,
 			)
 
+			leftText += renderFilesystems(metric.Filesystems)
+			leftText += renderPressure(metric)
+
 			// Add GPU information if available
 			if len(metric.GPUs) > 0 {
 				gpuSection := "\n\n[cyan]================================[-]\n[yellow]GPU Stats[-]\n[cyan]================================[-]\n"
@@ -275,46 +537,151 @@ func StartUI(metricsChan <-chan metrics.Metrics, quitChan chan<- struct{}) {
 					"[cyan]================================[-]\n[yellow]Memory Stats[-]\n[cyan]================================[-]\n"+
 					"[yellow]Memory Total:[-] %.1f GB\n"+
 					"[yellow]Memory Available:[-] %.1f GB\n"+
-					"[yellow]Memory Cached:[-] %.1f GB\n\n"+
-					"[cyan]================================[-]\n[yellow]Network Stats[-]\n[cyan]================================[-]\n"+
-					"[green]Sent MBps:[-] %.2f MB/s\n[green]%s[-]\n"+
-					"[blue]Recv MBps:[-] %.2f MB/s\n[blue]%s[-]\n\n"+
+					"[yellow]Memory Cached:[-] %.1f GB\n%s\n%s\n\n"+
+					"%s\n\n"+
 					"[cyan]================================[-]\n[yellow]Disk I/O[-]\n[cyan]================================[-]\n"+
-					"[yellow]Read:[-] %.2f MB/s\n[green]%s[-]\n[yellow]Write:[-] %.2f MB/s\n[green]%s[-]",
+					"[yellow]Read:[-] %.2f MB/s  %.0f IOPS\n[green]%s[-]\n[yellow]Write:[-] %.2f MB/s  %.0f IOPS\n[green]%s[-]%s",
 				renderBar("Memory", metric.MemoryUsage, 20),
 				memSpark,
 				float64(metric.MemoryTotal)/1024/1024/1024,
 				float64(metric.MemoryAvailable)/1024/1024/1024,
 				float64(metric.MemoryCached)/1024/1024/1024,
-				metric.NetSentMBps,
-				sentSpark,
-				metric.NetRecvMBps,
-				recvSpark,
-				metric.DiskReadMBps, diskReadSpark,
-				metric.DiskWriteMBps, diskWriteSpark,
+				renderMemoryBreakdown(metric),
+				renderSwap(metric),
+				networkView.render(metric),
+				metric.DiskReadMBps, metric.DiskReadIOPS, diskReadSpark,
+				metric.DiskWriteMBps, metric.DiskWriteIOPS, diskWriteSpark,
+				renderDiskDevices(metric.DiskDevices),
 			)
 
+			healthText := renderHealth(metric.Sources)
+			detailPID, detailText, detailOpen := detailView.refresh()
+			cgroupMessage := sourceMessage(metric, "cgroups")
+			connectionMessage := sourceMessage(metric, "connections")
+			sensorsText := renderSensors(metric)
+			if sensorsText == "" {
+				sensorsText = "[gray]" + tview.Escape(sourceMessage(metric, "temperature")) + "[-]"
+			}
+			connectionStatus, connectionsRead := metric.Source("connections")
+			connectionsRead = connectionsRead && connectionStatus.OK()
+			coresText := renderCores(metric.CPUPerCore)
+			containerLine := renderContainer(metric.Container)
+
 			app.QueueUpdateDraw(func() {
+				if containerLine != containerText {
+					containerText = containerLine
+					sysInfoBox.SetText(hostInfoText + containerText)
+				}
+				healthBox.SetText(healthText)
+				coresBox.SetText(coresText)
 				metricsBoxLeft.SetTextAlign(tview.AlignLeft)
 				metricsBoxRight.SetTextAlign(tview.AlignLeft)
 				metricsBoxLeft.SetText(leftText)
 				metricsBoxRight.SetText(rightText)
+				procView.update(metric.Processes)
+				cgroupView.update(cgroupRows(metric.Cgroups), cgroupMessage)
+				serviceView.update(serviceRows(metric.Cgroups), cgroupMessage)
+				userView.update(metrics.GroupByUser(metric.Processes), "")
+				connectionView.update(metric.Connections, connectionMessage)
+				listenerView.update(metric.Connections, connectionMessage, connectionsRead)
+				sensorsBox.SetText(sensorsText)
+				if detailOpen {
+					detailView.setText(detailPID, detailText)
+				}
 			})
 		}
 	}()
 
+	// Panels that can take focus for scrolling, in Tab order
+	focusable := []tview.Primitive{metricsBoxLeft, metricsBoxRight, coresBox}
+	focusIndex := 0
+
 	// Key Handler
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// An open dialog gets every key, so typing into it can't quit or switch pages
+		if procView.actions.dialogOpen() || filter.open {
+			return event
+		}
 		switch event.Rune() {
 		case 'q', 'Q':
 			close(quitChan)
 			app.Stop()
 			return nil
+		case '1':
+			showPage(pageOverview, focusable[focusIndex])
+			return nil
+		case '2':
+			showPage(pageProcesses, procView.table)
+			return nil
+		case '3':
+			showPage(pageCgroups, cgroupView.table)
+			return nil
+		case '4':
+			showPage(pageServices, serviceView.table)
+			return nil
+		case '5':
+			showPage(pageUsers, userView.table)
+			return nil
+		case '6':
+			showPage(pageConnections, connectionView.table)
+			return nil
+		case '7':
+			showPage(pageSensors, sensorsBox)
+			return nil
+		}
+
+		switch currentPage {
+		case pageOverview:
+			if event.Key() == tcell.KeyTab {
+				focusIndex = (focusIndex + 1) % len(focusable)
+				app.SetFocus(focusable[focusIndex])
+				return nil
+			}
+			if event.Rune() == 'n' || event.Rune() == 'N' {
+				networkView.cycle()
+				return nil
+			}
+		case pageProcesses:
+			if event.Rune() == '/' {
+				filter.start()
+				root.RemoveItem(footerBox)
+				root.AddItem(filter.input, 1, 0, true)
+				app.SetFocus(filter.input)
+				return nil
+			}
+			return procView.handleKey(event)
+		case pageCgroups:
+			return cgroupView.handleKey(event)
+		case pageServices:
+			return serviceView.handleKey(event)
+		case pageUsers:
+			return userView.handleKey(event)
+		case pageConnections:
+			if event.Key() == tcell.KeyTab {
+				if app.GetFocus() == connectionView.table {
+					app.SetFocus(listenerView.table)
+				} else {
+					app.SetFocus(connectionView.table)
+				}
+				return nil
+			}
+			if app.GetFocus() == connectionView.table {
+				return connectionView.handleKey(event)
+			}
+			return listenerView.handleKey(event)
+		case pageDetail:
+			if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 {
+				showPage(pageProcesses, procView.table)
+				return nil
+			}
+			if p, ok := detailView.selected(); ok && procView.actions.handleKey(event, p) {
+				return nil
+			}
 		}
 		return event
 	})
 
-	if err := app.SetRoot(flex, true).SetFocus(metricsBoxLeft).Run(); err != nil {
+	if err := app.SetRoot(root, true).SetFocus(metricsBoxLeft).Run(); err != nil {
 		panic(err)
 	}
 }
@@ -0,0 +1,45 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+var userColumns = []tableColumn[metrics.UserStats]{
+	{"CPU%", tview.AlignRight,
+		func(u metrics.UserStats) string { return fmt.Sprintf("%.1f", u.CPUPercent) },
+		func(a, b metrics.UserStats) bool { return a.CPUPercent < b.CPUPercent }},
+	{"RSS", tview.AlignRight,
+		func(u metrics.UserStats) string { return formatBytes(u.RSS) },
+		func(a, b metrics.UserStats) bool { return a.RSS < b.RSS }},
+	{"PROCS", tview.AlignRight,
+		func(u metrics.UserStats) string { return fmt.Sprint(u.Processes) },
+		func(a, b metrics.UserStats) bool { return a.Processes < b.Processes }},
+	{"THREADS", tview.AlignRight,
+		func(u metrics.UserStats) string { return fmt.Sprint(u.Threads) },
+		func(a, b metrics.UserStats) bool { return a.Threads < b.Threads }},
+	{"READ/s", tview.AlignRight,
+		func(u metrics.UserStats) string { return formatBytes(uint64(u.ReadBytesPerSec)) },
+		func(a, b metrics.UserStats) bool { return a.ReadBytesPerSec < b.ReadBytesPerSec }},
+	{"WRITE/s", tview.AlignRight,
+		func(u metrics.UserStats) string { return formatBytes(uint64(u.WriteBytesPerSec)) },
+		func(a, b metrics.UserStats) bool { return a.WriteBytesPerSec < b.WriteBytesPerSec }},
+	{"USER", tview.AlignLeft,
+		func(u metrics.UserStats) string { return u.User },
+		func(a, b metrics.UserStats) bool { return a.User < b.User }},
+}
+
+// newUserView returns the table ranking users by the resources their processes take
+func newUserView() *sortableTable[metrics.UserStats] {
+	t := newSortableTable("Users", userColumns,
+		func(u metrics.UserStats) string { return u.User },
+		func(a, b metrics.UserStats) bool { return a.User < b.User })
+	t.shortcuts = map[rune]sortShortcut{
+		'c': {"CPU%", true}, 'C': {"CPU%", true},
+		'm': {"RSS", true}, 'M': {"RSS", true},
+		'p': {"PROCS", true}, 'P': {"PROCS", true},
+	}
+	return t
+}
@@ -0,0 +1,197 @@
+package dashboard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/krisfur/go-resource-monitor/metrics"
+	"github.com/rivo/tview"
+)
+
+// WatchOptions configures the watch-mode dashboard
+type WatchOptions struct {
+	Title  string        // what's being watched, usually the command line
+	Output func() string // recent output of a spawned command, or nil when attached to a PID
+}
+
+// watchHistories are the sparkline histories of the watched tree
+type watchHistories struct {
+	cpu, rss, threads, fds, read, write, switches []float64
+}
+
+func (h *watchHistories) add(w metrics.WatchStats) {
+	addPoint(&h.cpu, w.CPUPercent)
+	addPoint(&h.rss, float64(w.RSS))
+	addPoint(&h.threads, float64(w.Threads))
+	addPoint(&h.fds, float64(w.FDs))
+	addPoint(&h.read, w.ReadBytesPerSec)
+	addPoint(&h.write, w.WriteBytesPerSec)
+	addPoint(&h.switches, w.ContextSwitchesPerSec)
+}
+
+func renderWatchHeader(w metrics.WatchStats, title string) string {
+	status := "[green]running[-]"
+	if w.Exited {
+		status = "[red]exited[-]"
+	}
+	elapsed := time.Since(w.Started).Truncate(time.Second)
+	return fmt.Sprintf("[yellow]PID %d[-] %s  %s\n"+
+		"[yellow]Elapsed:[-] %s  [yellow]Processes:[-] %d live, %d seen\n"+
+		"[yellow]CPU time:[-] %.2fs  [yellow]Peak RSS:[-] %s  [yellow]Read:[-] %s  [yellow]Written:[-] %s  [yellow]Context switches:[-] %d",
+		w.PID, tview.Escape(title), status,
+		elapsed, len(w.Processes), w.ProcessesSeen,
+		w.CPUSeconds, formatBytes(w.PeakRSS), formatBytes(w.ReadBytes), formatBytes(w.WriteBytes), w.ContextSwitches)
+}
+
+func renderWatchStats(w metrics.WatchStats, h *watchHistories) string {
+	var text strings.Builder
+	fmt.Fprintf(&text, "[yellow]CPU:[-] %.1f%%\n[green]%s[-]\n\n", w.CPUPercent, renderSparkline(normalizeHistory(h.cpu)))
+	fmt.Fprintf(&text, "[yellow]RSS:[-] %s\n[green]%s[-]\n\n", formatBytes(w.RSS), renderSparkline(normalizeHistory(h.rss)))
+	fmt.Fprintf(&text, "[yellow]Threads:[-] %d\n[green]%s[-]\n\n", w.Threads, renderSparkline(normalizeHistory(h.threads)))
+	fmt.Fprintf(&text, "[yellow]Open FDs:[-] %d\n[green]%s[-]\n\n", w.FDs, renderSparkline(normalizeHistory(h.fds)))
+	fmt.Fprintf(&text, "[yellow]Read:[-] %s/s\n[green]%s[-]\n\n", formatBytes(uint64(w.ReadBytesPerSec)), renderSparkline(normalizeHistory(h.read)))
+	fmt.Fprintf(&text, "[yellow]Write:[-] %s/s\n[blue]%s[-]\n\n", formatBytes(uint64(w.WriteBytesPerSec)), renderSparkline(normalizeHistory(h.write)))
+	fmt.Fprintf(&text, "[yellow]Context switches:[-] %.0f/s\n[green]%s[-]", w.ContextSwitchesPerSec, renderSparkline(normalizeHistory(h.switches)))
+	return text.String()
+}
+
+// StartWatchUI shows a dashboard scoped to the process tree in Metrics.Watch until the watched
+// process exits or the user quits, either way closing quitChan. It returns the last stats
+// received and whether the user quit before the process exited.
+func StartWatchUI(metricsChan <-chan metrics.Metrics, quitChan chan<- struct{}, opts WatchOptions) (metrics.WatchStats, bool) {
+	app := tview.NewApplication()
+
+	headerBox := tview.NewTextView()
+	headerBox.SetDynamicColors(true)
+	headerBox.SetBorder(true)
+	headerBox.SetTitle("Watching")
+	headerBox.SetWrap(false)
+	headerBox.SetText("Loading...")
+
+	statsBox := tview.NewTextView()
+	statsBox.SetDynamicColors(true)
+	statsBox.SetBorder(true)
+	statsBox.SetTitle("Resources")
+
+	procView := newProcessView()
+	procView.treeMode = true
+
+	var outputBox *tview.TextView
+	if opts.Output != nil {
+		outputBox = tview.NewTextView()
+		outputBox.SetBorder(true)
+		outputBox.SetTitle("Output")
+	}
+
+	footerBox := tview.NewTextView()
+	footerBox.SetDynamicColors(true)
+	footerBox.SetText("[yellow]Q stop watching  Tab switch panel  arrows scroll  </> sort column  R reverse  Space collapse")
+
+	// Layout
+	middle := tview.NewFlex().SetDirection(tview.FlexColumn)
+	middle.AddItem(statsBox, 0, 1, false)
+	middle.AddItem(procView.table, 0, 2, true)
+
+	root := tview.NewFlex().SetDirection(tview.FlexRow)
+	root.AddItem(headerBox, 5, 0, false)
+	root.AddItem(middle, 0, 1, true)
+	focusable := []tview.Primitive{procView.table, statsBox}
+	if outputBox != nil {
+		root.AddItem(outputBox, 12, 0, false)
+		focusable = append(focusable, outputBox)
+	}
+	root.AddItem(footerBox, 1, 0, false)
+	focusIndex := 0
+
+	var stopOnce sync.Once
+	stop := func() {
+		stopOnce.Do(func() {
+			close(quitChan)
+			app.Stop()
+		})
+	}
+
+	// Written on the UI goroutine, read once Run has returned
+	var last metrics.WatchStats
+	userQuit := false
+
+	histories := &watchHistories{}
+	go func() {
+		for metric := range metricsChan {
+			w := metric.Watch
+			histories.add(w)
+			headerText := renderWatchHeader(w, opts.Title)
+			statsText := renderWatchStats(w, histories)
+			var outputText string
+			if opts.Output != nil {
+				outputText = opts.Output()
+			}
+
+			app.QueueUpdateDraw(func() {
+				last = w
+				headerBox.SetText(headerText)
+				statsBox.SetText(statsText)
+				procView.update(w.Processes)
+				if outputBox != nil {
+					outputBox.SetText(outputText)
+					outputBox.ScrollToEnd()
+				}
+				if w.Exited {
+					stop()
+				}
+			})
+		}
+	}()
+
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Rune() == 'q' || event.Rune() == 'Q':
+			userQuit = true
+			stop()
+			return nil
+		case event.Key() == tcell.KeyTab:
+			focusIndex = (focusIndex + 1) % len(focusable)
+			app.SetFocus(focusable[focusIndex])
+			return nil
+		}
+		if app.GetFocus() == procView.table {
+			return procView.handleKey(event)
+		}
+		return event
+	})
+
+	if err := app.SetRoot(root, true).SetFocus(procView.table).Run(); err != nil {
+		panic(err)
+	}
+	return last, userQuit
+}
+
+// PrintWatchSummary reports on a watched process, /usr/bin/time style. state is only known
+// for a command we started, and gives exact figures where sampling can miss short-lived work.
+func PrintWatchSummary(w io.Writer, title string, stats metrics.WatchStats, wall time.Duration, state *os.ProcessState) {
+	fmt.Fprintf(w, "\nCommand:           %s\n", title)
+	if state != nil {
+		fmt.Fprintf(w, "Exit status:       %s\n", state)
+	}
+	fmt.Fprintf(w, "Wall time:         %.2fs\n", wall.Seconds())
+	if state != nil {
+		user, system := state.UserTime().Seconds(), state.SystemTime().Seconds()
+		fmt.Fprintf(w, "CPU time:          %.2fs (user %.2fs, system %.2fs)\n", user+system, user, system)
+	} else {
+		fmt.Fprintf(w, "CPU time:          %.2fs\n", stats.CPUSeconds)
+	}
+	fmt.Fprintf(w, "Peak RSS (tree):   %s\n", formatBytes(stats.PeakRSS))
+	if state != nil {
+		if maxRSS, ok := metrics.MaxRSS(state); ok {
+			fmt.Fprintf(w, "Max RSS (process): %s\n", formatBytes(maxRSS))
+		}
+	}
+	fmt.Fprintf(w, "Read / written:    %s / %s\n", formatBytes(stats.ReadBytes), formatBytes(stats.WriteBytes))
+	fmt.Fprintf(w, "Context switches:  %d\n", stats.ContextSwitches)
+	fmt.Fprintf(w, "Processes seen:    %d\n", stats.ProcessesSeen)
+}
@@ -1,15 +1,69 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/krisfur/go-resource-monitor/dashboard"
 	"github.com/krisfur/go-resource-monitor/metrics"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		os.Exit(runWatch(os.Args[2:]))
+	}
+
+	netInclude := flag.String("net-include", "", "comma-separated network interface patterns to show, e.g. \"eth*,wlan0\" (default all)")
+	netExclude := flag.String("net-exclude", "", "comma-separated network interface patterns to hide, e.g. \"lo,veth*,docker*\"")
+	fsInclude := flag.String("fs-include", "", "comma-separated mount point patterns to show, e.g. \"/,/home,/data*\" (default all)")
+	fsExclude := flag.String("fs-exclude", "", "comma-separated mount point patterns to hide, e.g. \"/boot*,/snap/*\"")
+	cgroupRoot := flag.String("cgroup-root", "", "where the cgroup v2 hierarchy is mounted (default /sys/fs/cgroup)")
+	limits := flag.String("limits", "auto", "what CPU and memory percentages are relative to: auto (the container's limits when in one), host or cgroup")
+	cpuSensor := flag.String("cpu-sensor", "", "which sensor is CPU Temp, as a chip/label pattern from the sensors page, e.g. \"k10temp/Tctl\" (default picks the CPU package sensor)")
+	filter := flag.String("filter", "", "process filter, e.g. \"java && cpu>20 || user=postgres\" (also set with / in the process view)")
+	flag.Parse()
+
+	if _, err := metrics.ParseProcessFilter(*filter); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -filter: %v\n", err)
+		os.Exit(2)
+	}
+	if err := metrics.ValidateSensorPattern(*cpuSensor); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -cpu-sensor: %v\n", err)
+		os.Exit(2)
+	}
+	limitsMode, err := metrics.ParseLimitsMode(*limits)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -limits: %v\n", err)
+		os.Exit(2)
+	}
+
+	registry := metrics.DefaultRegistry()
+	registry.Register(metrics.NewNetworkCollector(splitList(*netInclude), splitList(*netExclude)))
+	registry.Register(metrics.NewFilesystemCollector(splitList(*fsInclude), splitList(*fsExclude)))
+	registry.Register(metrics.NewCgroupCollector(*cgroupRoot))
+	registry.Register(metrics.NewContainerCollector(limitsMode, *cgroupRoot))
+	registry.Register(metrics.NewTemperatureCollector(*cpuSensor))
+
 	metricsChan := make(chan metrics.Metrics)
 	quitChan := make(chan struct{})
 
-	go metrics.CollectMetrics(metricsChan, quitChan)
+	go func() {
+		registry.Run(metricsChan, quitChan)
+		close(metricsChan) // ends the dashboard's metrics loop
+	}()
+
+	dashboard.StartUI(metricsChan, quitChan, dashboard.Options{ProcessFilter: *filter})
+}
 
-	dashboard.StartUI(metricsChan, quitChan)
+// splitList parses a comma-separated flag value, ignoring empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
@@ -0,0 +1,285 @@
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultCgroupRoot is where cgroup v2 is mounted on a unified system; hybrid systems mount
+// it at unified/ underneath instead
+const defaultCgroupRoot = "/sys/fs/cgroup"
+
+// CgroupStats is the resource usage of one cgroup v2 group
+type CgroupStats struct {
+	Path string // relative to the cgroup root, e.g. "/system.slice/nginx.service"; "/" is the root
+
+	// From cpu.stat and cpu.max
+	CPUPercent       float64 // 100 is one CPU, from the change in usage_usec
+	CPUUsageUsec     uint64
+	CPULimit         float64 // quota in CPUs from cpu.max, 0 when unlimited
+	NrPeriods        uint64
+	NrThrottled      uint64
+	ThrottledUsec    uint64
+	ThrottledPercent float64 // share of enforcement periods throttled since the previous sample
+
+	// From memory.current, memory.max, memory.high and memory.events; limits are Unlimited when unset
+	MemoryCurrent uint64
+	MemoryMax     uint64
+	MemoryHigh    uint64
+	MemoryEvents  CgroupMemoryEvents
+
+	// From io.stat, summed over devices
+	IO                 []CgroupIOStat
+	IOReadBytesPerSec  float64
+	IOWriteBytesPerSec float64
+
+	Tasks uint64 // pids.current, or the number of processes when the pids controller is off
+}
+
+// CgroupMemoryEvents are the counters in memory.events
+type CgroupMemoryEvents struct {
+	Low     uint64
+	High    uint64
+	Max     uint64
+	OOM     uint64
+	OOMKill uint64
+}
+
+// CgroupIOStat is one device line of io.stat
+type CgroupIOStat struct {
+	Device string // major:minor
+	RBytes uint64
+	WBytes uint64
+	RIOs   uint64
+	WIOs   uint64
+}
+
+// cgroupCounters are the cumulative values rates are worked out from
+type cgroupCounters struct {
+	usage, periods, throttled, read, write uint64
+}
+
+// cgroupCollector walks a cgroup v2 hierarchy
+type cgroupCollector struct {
+	root     string
+	prev     map[string]cgroupCounters
+	prevTime time.Time
+}
+
+// NewCgroupCollector returns a collector for the cgroup v2 hierarchy mounted at root. An empty
+// root means /sys/fs/cgroup, or /sys/fs/cgroup/unified on a hybrid v1/v2 system.
+func NewCgroupCollector(root string) Collector {
+	return &cgroupCollector{root: root}
+}
+
+func (c *cgroupCollector) Name() string { return "cgroups" }
+
+func (c *cgroupCollector) Collect(m *Metrics) error {
+	root, err := findCgroupRoot(c.root)
+	if err != nil {
+		m.Cgroups = nil
+		return err
+	}
+
+	now := time.Now()
+	var elapsed float64
+	if !c.prevTime.IsZero() {
+		elapsed = now.Sub(c.prevTime).Seconds()
+	}
+
+	var cgroups []CgroupStats
+	current := make(map[string]cgroupCounters)
+	err = filepath.WalkDir(root, func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// A cgroup removed mid-walk isn't a failure
+			if errors.Is(err, fs.ErrNotExist) && dir != root {
+				return nil
+			}
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		path := "/"
+		if rel, _ := filepath.Rel(root, dir); rel != "." {
+			path += filepath.ToSlash(rel)
+		}
+		stats := readCgroup(dir, path)
+
+		counters := cgroupCounters{usage: stats.CPUUsageUsec, periods: stats.NrPeriods, throttled: stats.NrThrottled}
+		for _, io := range stats.IO {
+			counters.read += io.RBytes
+			counters.write += io.WBytes
+		}
+		if prev, ok := c.prev[stats.Path]; ok && elapsed > 0 {
+			stats.CPUPercent = rate(prev.usage, counters.usage, elapsed) / 1e6 * 100
+			if periods := delta(prev.periods, counters.periods); periods > 0 {
+				stats.ThrottledPercent = delta(prev.throttled, counters.throttled) / periods * 100
+			}
+			stats.IOReadBytesPerSec = rate(prev.read, counters.read, elapsed)
+			stats.IOWriteBytesPerSec = rate(prev.write, counters.write, elapsed)
+		}
+		current[stats.Path] = counters
+		cgroups = append(cgroups, stats)
+		return nil
+	})
+	if err != nil {
+		m.Cgroups = nil
+		return fmt.Errorf("walk cgroups: %w", err)
+	}
+
+	m.Cgroups = cgroups
+	c.prev, c.prevTime = current, now
+	return nil
+}
+
+// findCgroupRoot checks root is a cgroup v2 hierarchy, trying the hybrid location when no
+// root was given
+func findCgroupRoot(root string) (string, error) {
+	candidates := []string{root}
+	if root == "" {
+		candidates = []string{defaultCgroupRoot, filepath.Join(defaultCgroupRoot, "unified")}
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(filepath.Join(candidate, "cgroup.controllers")); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no cgroup v2 hierarchy at %s", ErrUnavailable, candidates[0])
+}
+
+// readCgroup reads the interface files of one cgroup. Files a controller doesn't provide,
+// such as memory.current on the root, are left at zero.
+func readCgroup(dir, path string) CgroupStats {
+	stats := CgroupStats{Path: path, MemoryMax: Unlimited, MemoryHigh: Unlimited}
+
+	cpu := readKeyValueFile(filepath.Join(dir, "cpu.stat"))
+	stats.CPUUsageUsec = cpu["usage_usec"]
+	stats.NrPeriods = cpu["nr_periods"]
+	stats.NrThrottled = cpu["nr_throttled"]
+	stats.ThrottledUsec = cpu["throttled_usec"]
+	stats.CPULimit = readCPUMax(filepath.Join(dir, "cpu.max"))
+
+	stats.MemoryCurrent, _ = readCgroupValue(filepath.Join(dir, "memory.current"))
+	if limit, ok := readCgroupValue(filepath.Join(dir, "memory.max")); ok {
+		stats.MemoryMax = limit
+	}
+	if limit, ok := readCgroupValue(filepath.Join(dir, "memory.high")); ok {
+		stats.MemoryHigh = limit
+	}
+	events := readKeyValueFile(filepath.Join(dir, "memory.events"))
+	stats.MemoryEvents = CgroupMemoryEvents{
+		Low:     events["low"],
+		High:    events["high"],
+		Max:     events["max"],
+		OOM:     events["oom"],
+		OOMKill: events["oom_kill"],
+	}
+
+	stats.IO = readIOStat(filepath.Join(dir, "io.stat"))
+
+	if tasks, ok := readCgroupValue(filepath.Join(dir, "pids.current")); ok {
+		stats.Tasks = tasks
+	} else if procs, err := os.ReadFile(filepath.Join(dir, "cgroup.procs")); err == nil {
+		stats.Tasks = uint64(len(strings.Fields(string(procs))))
+	}
+	return stats
+}
+
+// readCgroupValue reads a single-value file, where "max" means Unlimited
+func readCgroupValue(path string) (uint64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return Unlimited, true
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	return n, err == nil
+}
+
+// readCPUMax turns cpu.max ("quota period", or "max period") into a number of CPUs
+func readCPUMax(path string) float64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+	quota, err1 := strconv.ParseFloat(fields[0], 64)
+	period, err2 := strconv.ParseFloat(fields[1], 64)
+	if err1 != nil || err2 != nil || period <= 0 {
+		return 0
+	}
+	return quota / period
+}
+
+// readKeyValueFile parses files of "key value" lines such as cpu.stat and memory.events
+func readKeyValueFile(path string) map[string]uint64 {
+	values := make(map[string]uint64)
+	f, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = n
+		}
+	}
+	return values
+}
+
+// readIOStat parses io.stat lines like "8:0 rbytes=1024 wbytes=0 rios=1 wios=0 dbytes=0 dios=0"
+func readIOStat(path string) []CgroupIOStat {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var devices []CgroupIOStat
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		device := CgroupIOStat{Device: fields[0]}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				device.RBytes = n
+			case "wbytes":
+				device.WBytes = n
+			case "rios":
+				device.RIOs = n
+			case "wios":
+				device.WIOs = n
+			}
+		}
+		devices = append(devices, device)
+	}
+	return devices
+}
@@ -0,0 +1,189 @@
+package metrics
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// copyTree copies a fixture directory, so a test can change its files between samples
+func copyTree(t *testing.T, src string) string {
+	t.Helper()
+	dst := t.TempDir()
+	err := filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dst
+}
+
+func writeFixture(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// cgroupsByPath indexes a sample's cgroups by path
+func cgroupsByPath(m Metrics) map[string]CgroupStats {
+	byPath := make(map[string]CgroupStats)
+	for _, cg := range m.Cgroups {
+		byPath[cg.Path] = cg
+	}
+	return byPath
+}
+
+func TestCgroupCollectorFixture(t *testing.T) {
+	var m Metrics
+	if err := NewCgroupCollector(filepath.Join("testdata", "cgroup")).Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	byPath := cgroupsByPath(m)
+	var paths []string
+	for _, cg := range m.Cgroups {
+		paths = append(paths, cg.Path)
+	}
+	wantPaths := []string{"/", "/system.slice", "/system.slice/nginx.service", "/user.slice"}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Fatalf("paths = %v, want %v", paths, wantPaths)
+	}
+
+	root := byPath["/"]
+	if root.CPUUsageUsec != 90000000 || root.MemoryMax != Unlimited || root.CPULimit != 0 {
+		t.Errorf("root = %+v, want usage 90000000 and no limits", root)
+	}
+
+	nginx := byPath["/system.slice/nginx.service"]
+	if nginx.CPUUsageUsec != 2000000 || nginx.NrPeriods != 200 || nginx.NrThrottled != 20 || nginx.ThrottledUsec != 40000 {
+		t.Errorf("nginx cpu.stat = %d/%d/%d/%d, want 2000000/200/20/40000",
+			nginx.CPUUsageUsec, nginx.NrPeriods, nginx.NrThrottled, nginx.ThrottledUsec)
+	}
+	if nginx.CPULimit != 0.5 {
+		t.Errorf("nginx cpu.max limit = %v CPUs, want 0.5", nginx.CPULimit)
+	}
+	if nginx.MemoryCurrent != 100<<20 || nginx.MemoryMax != 256<<20 || nginx.MemoryHigh != 192<<20 {
+		t.Errorf("nginx memory = %d/%d/%d, want 100 MiB current, 256 MiB max, 192 MiB high",
+			nginx.MemoryCurrent, nginx.MemoryMax, nginx.MemoryHigh)
+	}
+	if want := (CgroupMemoryEvents{High: 3, Max: 1}); nginx.MemoryEvents != want {
+		t.Errorf("nginx memory.events = %+v, want %+v", nginx.MemoryEvents, want)
+	}
+	wantIO := []CgroupIOStat{
+		{Device: "8:0", RBytes: 4096, WBytes: 8192, RIOs: 1, WIOs: 2},
+		{Device: "259:0", RBytes: 1024, RIOs: 1},
+	}
+	if !reflect.DeepEqual(nginx.IO, wantIO) {
+		t.Errorf("nginx io.stat = %+v, want %+v", nginx.IO, wantIO)
+	}
+	if nginx.Tasks != 5 {
+		t.Errorf("nginx tasks = %d, want 5 from pids.current", nginx.Tasks)
+	}
+	if nginx.CPUPercent != 0 || nginx.IOReadBytesPerSec != 0 {
+		t.Errorf("first sample has rates %v%% and %v B/s, want none", nginx.CPUPercent, nginx.IOReadBytesPerSec)
+	}
+
+	user := byPath["/user.slice"]
+	if user.CPULimit != 0 || user.MemoryMax != Unlimited {
+		t.Errorf("user.slice with \"max\" limits = %v CPUs, %d bytes, want unlimited", user.CPULimit, user.MemoryMax)
+	}
+	if user.Tasks != 3 {
+		t.Errorf("user.slice tasks = %d, want 3 from cgroup.procs", user.Tasks)
+	}
+}
+
+func TestCgroupCollectorRates(t *testing.T) {
+	root := copyTree(t, filepath.Join("testdata", "cgroup"))
+	c := NewCgroupCollector(root).(*cgroupCollector)
+	var m Metrics
+	if err := c.Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	// A second later nginx has used half a CPU second, been throttled in 25 of 50 periods and
+	// read 1 MiB and written 2 MiB more on 8:0
+	c.prevTime = c.prevTime.Add(-time.Second)
+	nginx := filepath.Join(root, "system.slice", "nginx.service")
+	writeFixture(t, filepath.Join(nginx, "cpu.stat"),
+		"usage_usec 2500000\nnr_periods 250\nnr_throttled 45\nthrottled_usec 90000\n")
+	writeFixture(t, filepath.Join(nginx, "io.stat"),
+		"8:0 rbytes=1052672 wbytes=2105344 rios=9 wios=12\n259:0 rbytes=1024 wbytes=0 rios=1 wios=0\n")
+	if err := c.Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	stats := cgroupsByPath(m)["/system.slice/nginx.service"]
+	near := func(got, want float64) bool { return math.Abs(got-want) <= want*0.05 }
+	if !near(stats.CPUPercent, 50) {
+		t.Errorf("CPU = %.2f%%, want about 50%%", stats.CPUPercent)
+	}
+	if stats.ThrottledPercent != 50 {
+		t.Errorf("throttled = %.2f%% of periods, want 50%%", stats.ThrottledPercent)
+	}
+	if !near(stats.IOReadBytesPerSec, 1<<20) || !near(stats.IOWriteBytesPerSec, 2<<20) {
+		t.Errorf("IO = %.0f read, %.0f write B/s, want about 1 MiB and 2 MiB", stats.IOReadBytesPerSec, stats.IOWriteBytesPerSec)
+	}
+	if other := cgroupsByPath(m)["/user.slice"]; other.CPUPercent != 0 {
+		t.Errorf("unchanged user.slice has CPU %.2f%%, want 0", other.CPUPercent)
+	}
+
+	// A cgroup recreated under the same name starts its counters again; that is no activity,
+	// not a huge rate
+	writeFixture(t, filepath.Join(nginx, "cpu.stat"), "usage_usec 100\n")
+	if err := c.Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if got := cgroupsByPath(m)["/system.slice/nginx.service"].CPUPercent; got != 0 {
+		t.Errorf("CPU after a counter reset = %.2f%%, want 0", got)
+	}
+}
+
+func TestCgroupCollectorUnavailable(t *testing.T) {
+	var m Metrics
+	err := NewCgroupCollector(t.TempDir()).Collect(&m)
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Collect on a directory without cgroup.controllers = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestReadCPUMax(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		content string
+		want    float64
+	}{
+		{"max 100000\n", 0},
+		{"50000 100000\n", 0.5},
+		{"200000 100000\n", 2},
+		{"150000 50000\n", 3},
+		{"garbage\n", 0},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, "cpu.max")
+		writeFixture(t, path, tt.content)
+		if got := readCPUMax(path); got != tt.want {
+			t.Errorf("readCPUMax(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+	if got := readCPUMax(filepath.Join(dir, "missing")); got != 0 {
+		t.Errorf("readCPUMax on a missing file = %v, want 0", got)
+	}
+}
@@ -1,19 +1,16 @@
 package metrics
 
 import (
-	"os/exec"
-	"strconv"
-	"strings"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
 	"time"
-
-	"github.com/distatus/battery"
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/disk"
-	"github.com/shirou/gopsutil/v3/host"
-	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/net"
 )
 
+// tickInterval is how often the registry emits a Metrics sample
+const tickInterval = 1 * time.Second
+
 type Metrics struct {
 	CPUUsage       float64
 	MemoryUsage    float64
@@ -27,8 +24,8 @@ type Metrics struct {
 	// New metrics
 	DiskReadMBps       float64
 	DiskWriteMBps      float64
-	DiskReadOps        uint64
-	DiskWriteOps       uint64
+	DiskReadIOPS       float64
+	DiskWriteIOPS      float64
 	MemoryTotal        uint64
 	MemoryAvailable    uint64
 	MemoryCached       uint64
@@ -39,8 +36,71 @@ type Metrics struct {
 	UptimeHours        int
 	UptimeMinutes      int
 
+	// CPU detail
+	CPUPerCore   []float64 // utilisation of each logical CPU, in percent
+	CPUBreakdown CPUTimeShares
+
+	// Load and run queue
+	Load1                 float64
+	Load5                 float64
+	Load15                float64
+	ProcsRunning          int
+	ProcsBlocked          int
+	ContextSwitchesPerSec float64
+	InterruptsPerSec      float64
+
+	// Where RAM went, beyond the totals above
+	MemoryBreakdown MemoryBreakdown
+
+	// Swap, with SwapUsage above as the used percentage
+	SwapTotal         uint64
+	SwapUsed          uint64
+	SwapDevices       []SwapDeviceInfo
+	SwapInMBps        float64
+	SwapOutMBps       float64
+	MajorFaultsPerSec float64
+
+	// Every real mounted filesystem; DiskUsage above is the root filesystem's
+	Filesystems []FilesystemStats
+
+	// Per-device disk I/O; the Disk totals above sum the devices that aren't stacked
+	DiskDevices []DiskDeviceStats
+
+	// Per-interface network stats; NetSentMBps and NetRecvMBps are the sums over these
+	NetInterfaces []NetInterfaceStats
+
+	// Retransmits, resets, drops and socket usage from the kernel's protocol counters (Linux only)
+	NetHealth NetHealth
+
+	// Pressure Stall Information for cpu, memory and io (Linux only)
+	Pressure []PressureStats
+
 	// GPU metrics
 	GPUs []GPUInfo
+
+	// Every hardware monitoring chip (Linux only). CPUTemp above is the sensor named by
+	// CPUTempSensor, and CoreTemps are the per-core readings where the CPU driver has them.
+	Sensors       []SensorChip
+	CPUTempSensor string
+	CoreTemps     []TempSensor
+
+	// Every running process
+	Processes []ProcessInfo
+
+	// Every TCP and UDP socket, with its owning process where known (Linux only)
+	Connections []Connection
+
+	// Every cgroup in the cgroup v2 hierarchy, parents before children (Linux only)
+	Cgroups []CgroupStats
+
+	// The cgroup the monitor runs in, and whether CPUUsage and MemoryUsage are scaled to its limits
+	Container ContainerInfo
+
+	// The process tree followed by watch mode, when a watch collector is registered
+	Watch WatchStats
+
+	// Status of every collector that contributed to this sample, in registration order
+	Sources []SourceStatus
 }
 
 type GPUInfo struct {
@@ -51,189 +111,227 @@ type GPUInfo struct {
 	Utilization float64
 }
 
-func CollectMetrics(metricsChan chan<- Metrics, quitChan <-chan struct{}) {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
+// ErrUnavailable is returned (possibly wrapped) by collectors whose source doesn't exist on this
+// machine, such as a battery on a desktop, so it can be told apart from a real failure.
+var ErrUnavailable = errors.New("not available")
 
-	var prevNetSent, prevNetRecv uint64
-	var prevDiskRead, prevDiskWrite uint64
+// SourceStatus records how a collector fared the last time it ran
+type SourceStatus struct {
+	Name     string
+	Err      error
+	LastRun  time.Time
+	Duration time.Duration
+}
 
-	for {
-		select {
-		case <-ticker.C:
-			cpuPercent, _ := cpu.Percent(0, false)
-
-			memStats, _ := mem.VirtualMemory()
-			diskStats, _ := disk.Usage("/")
-
-			netIO, _ := net.IOCounters(false)
-			var sentMBps, recvMBps float64
-			var packetsSent, packetsRecv uint64
-			if len(netIO) > 0 {
-				sent := netIO[0].BytesSent
-				recv := netIO[0].BytesRecv
-				packetsSent = netIO[0].PacketsSent
-				packetsRecv = netIO[0].PacketsRecv
-
-				sentMBps = float64(sent-prevNetSent) / 1024 / 1024
-				recvMBps = float64(recv-prevNetRecv) / 1024 / 1024
-
-				prevNetSent = sent
-				prevNetRecv = recv
-			}
+// OK reports whether the last collection succeeded
+func (s SourceStatus) OK() bool {
+	return s.Err == nil
+}
 
-			// Disk I/O
-			diskIO, _ := disk.IOCounters()
-			var diskReadMBps, diskWriteMBps float64
-			var diskReadOps, diskWriteOps uint64
-			if len(diskIO) > 0 {
-				// Sum up all disk I/O
-				var totalRead, totalWrite uint64
-				for _, io := range diskIO {
-					totalRead += io.ReadBytes
-					totalWrite += io.WriteBytes
-					diskReadOps += io.ReadCount
-					diskWriteOps += io.WriteCount
-				}
+// Unavailable reports whether the source doesn't exist on this machine
+func (s SourceStatus) Unavailable() bool {
+	return errors.Is(s.Err, ErrUnavailable)
+}
 
-				diskReadMBps = float64(totalRead-prevDiskRead) / 1024 / 1024
-				diskWriteMBps = float64(totalWrite-prevDiskWrite) / 1024 / 1024
+// Source returns the status of the named collector, if it ran for this sample
+func (m Metrics) Source(name string) (SourceStatus, bool) {
+	for _, s := range m.Sources {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return SourceStatus{}, false
+}
 
-				prevDiskRead = totalRead
-				prevDiskWrite = totalWrite
-			}
+// Collector gathers one group of readings (CPU, memory, network...) into a Metrics sample.
+//
+// The sample passed to Collect still holds the values from the previous tick, so a
+// collector must replace any slices or maps it owns rather than modifying them in place.
+type Collector interface {
+	Name() string
+	Collect(m *Metrics) error
+}
 
-			// CPU Temperature - now using platform-specific implementation
-			cpuTemp := GetCPUTemperature()
-
-			// Battery
-			batteryPercent := 0.0
-			batteryState := "N/A"
-			batStats, _ := battery.GetAll()
-			if len(batStats) > 0 {
-				bat := batStats[0]
-				batteryPercent = (bat.Current / bat.Full) * 100
-				batteryState = bat.State.String()
-			}
+// IntervalCollector is implemented by collectors that don't need to run every tick.
+// Between runs the sample keeps the values from their last collection.
+type IntervalCollector interface {
+	Collector
+	Interval() time.Duration
+}
 
-			// Uptime
-			hostInfo, _ := host.Info()
-			uptime := hostInfo.Uptime
-			uptimeDays := int(uptime / 86400)
-			uptimeHours := int((uptime % 86400) / 3600)
-			uptimeMinutes := int((uptime % 3600) / 60)
-
-			// GPU Information - Detect GPUs from temperature sensors and system info
-			var gpus []GPUInfo
-
-			// Get temperature sensors for GPU detection
-			temps, _ := host.SensorsTemperatures()
-
-			// First, detect GPUs from temperature sensors
-			for _, t := range temps {
-				sensorKey := strings.ToLower(t.SensorKey)
-				if strings.Contains(sensorKey, "gpu") ||
-					strings.Contains(sensorKey, "amdgpu") ||
-					strings.Contains(sensorKey, "nvidia") ||
-					strings.Contains(sensorKey, "edge") { // AMD GPU edge temperature
-
-					// Extract GPU name from sensor key
-					gpuName := "GPU"
-					if strings.Contains(sensorKey, "amdgpu") {
-						gpuName = "AMD GPU"
-					} else if strings.Contains(sensorKey, "nvidia") {
-						gpuName = "NVIDIA GPU"
-					} else if strings.Contains(sensorKey, "edge") {
-						gpuName = "AMD GPU"
-					}
-
-					gpus = append(gpus, GPUInfo{
-						Name:        gpuName,
-						MemoryUsed:  0, // Not available without GPU-specific libraries
-						MemoryTotal: 0, // Not available without GPU-specific libraries
-						Temperature: t.Temperature,
-						Utilization: 0, // Not available without GPU-specific libraries
-					})
-				}
-			}
+// Registry holds the collectors that make up each Metrics sample
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+	disabled   map[string]bool
+}
 
-			// Check for NVIDIA GPU via nvidia-smi if not already detected
-			nvidiaDetected := false
-			for _, gpu := range gpus {
-				if strings.Contains(strings.ToLower(gpu.Name), "nvidia") {
-					nvidiaDetected = true
-					break
-				}
-			}
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{disabled: make(map[string]bool)}
+}
 
-			if !nvidiaDetected {
-				// Try to get NVIDIA GPU temperature and utilization via nvidia-smi
-				tempCmd := exec.Command("nvidia-smi", "--query-gpu=temperature.gpu", "--format=csv,noheader,nounits")
-				utilCmd := exec.Command("nvidia-smi", "--query-gpu=utilization.gpu", "--format=csv,noheader,nounits")
+// DefaultRegistry returns a registry with all built-in collectors registered
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, c := range builtinCollectors() {
+		r.Register(c)
+	}
+	return r
+}
 
-				var temp, util float64
-				if output, err := tempCmd.Output(); err == nil {
-					if tempStr := strings.TrimSpace(string(output)); tempStr != "" {
-						temp, _ = strconv.ParseFloat(tempStr, 64)
-					}
-				}
-				if output, err := utilCmd.Output(); err == nil {
-					if utilStr := strings.TrimSpace(string(output)); utilStr != "" {
-						util, _ = strconv.ParseFloat(strings.TrimRight(utilStr, " %"), 64)
-					}
-				}
+func builtinCollectors() []Collector {
+	return []Collector{
+		&cpuCollector{},
+		&loadCollector{},
+		&memoryCollector{},
+		&swapCollector{},
+		&pressureCollector{},
+		NewFilesystemCollector(nil, nil),
+		&diskIOCollector{},
+		NewNetworkCollector(nil, nil),
+		&netHealthCollector{},
+		NewTemperatureCollector(""),
+		&batteryCollector{},
+		&uptimeCollector{},
+		&gpuCollector{},
+		NewProcessCollector(),
+		NewConnectionCollector(),
+		NewCgroupCollector(""),
+		NewContainerCollector(LimitsAuto, ""),
+	}
+}
 
-				if temp > 0 || util > 0 {
-					gpus = append(gpus, GPUInfo{
-						Name:        "NVIDIA GPU",
-						Temperature: temp,
-						Utilization: util,
-					})
-				}
-			}
+// Register adds a collector, replacing any existing collector with the same name
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.collectors {
+		if existing.Name() == c.Name() {
+			r.collectors[i] = c
+			return
+		}
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// Disable stops a collector from running without removing it from the registry
+func (r *Registry) Disable(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.disabled[name] = true
+}
 
-			// AMD GPU Utilization
-			for i, gpu := range gpus {
-				if strings.Contains(strings.ToLower(gpu.Name), "amd") {
-					// Try to read utilization from sysfs
-					if utilData, err := exec.Command("cat", "/sys/class/drm/card0/device/gpu_busy_percent").Output(); err == nil {
-						if utilStr := strings.TrimSpace(string(utilData)); utilStr != "" {
-							gpus[i].Utilization, _ = strconv.ParseFloat(utilStr, 64)
-						}
-					}
+// Enable re-enables a collector previously stopped with Disable
+func (r *Registry) Enable(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.disabled, name)
+}
+
+// Collectors returns the enabled collectors in registration order
+func (r *Registry) Collectors() []Collector {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var enabled []Collector
+	for _, c := range r.collectors {
+		if !r.disabled[c.Name()] {
+			enabled = append(enabled, c)
+		}
+	}
+	return enabled
+}
+
+// Run collects a sample every tick and sends it on metricsChan until quitChan is closed
+func (r *Registry) Run(metricsChan chan<- Metrics, quitChan <-chan struct{}) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+
+	var sample Metrics
+	statuses := make(map[string]SourceStatus)
+
+	for {
+		select {
+		case now := <-ticker.C:
+			collectors := r.Collectors()
+			sources := make([]SourceStatus, 0, len(collectors))
+			for _, c := range collectors {
+				status, ran := statuses[c.Name()]
+				due := true
+				if ic, ok := c.(IntervalCollector); ok && ran {
+					due = now.Sub(status.LastRun) >= ic.Interval()
+				}
+				if due {
+					status = runCollector(c, &sample, now)
+					statuses[c.Name()] = status
 				}
+				sources = append(sources, status)
 			}
+			sample.Sources = sources
 
-			metricsChan <- Metrics{
-				CPUUsage:       cpuPercent[0],
-				MemoryUsage:    memStats.UsedPercent,
-				DiskUsage:      diskStats.UsedPercent,
-				NetSentMBps:    sentMBps,
-				NetRecvMBps:    recvMBps,
-				CPUTemp:        cpuTemp,
-				BatteryPercent: batteryPercent,
-				BatteryState:   batteryState,
-
-				// New metrics
-				DiskReadMBps:       diskReadMBps,
-				DiskWriteMBps:      diskWriteMBps,
-				DiskReadOps:        diskReadOps,
-				DiskWriteOps:       diskWriteOps,
-				MemoryTotal:        memStats.Total,
-				MemoryAvailable:    memStats.Available,
-				MemoryCached:       memStats.Cached,
-				SwapUsage:          0.0, // TODO: Fix swap usage calculation
-				NetworkPacketsSent: packetsSent,
-				NetworkPacketsRecv: packetsRecv,
-				UptimeDays:         uptimeDays,
-				UptimeHours:        uptimeHours,
-				UptimeMinutes:      uptimeMinutes,
-
-				// GPU metrics
-				GPUs: gpus,
+			select {
+			case metricsChan <- sample:
+			case <-quitChan:
+				return
 			}
 		case <-quitChan:
 			return
 		}
 	}
 }
+
+// runCollector runs a single collector, turning a panic into an error so one broken
+// source can't take the whole monitor down
+func runCollector(c Collector, m *Metrics, now time.Time) (status SourceStatus) {
+	status = SourceStatus{Name: c.Name(), LastRun: now}
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			status.Err = fmt.Errorf("panic: %v", r)
+		}
+		status.Duration = time.Since(start)
+	}()
+	status.Err = c.Collect(m)
+	return status
+}
+
+// CollectMetrics runs the built-in collectors, sending a sample every second
+func CollectMetrics(metricsChan chan<- Metrics, quitChan <-chan struct{}) {
+	DefaultRegistry().Run(metricsChan, quitChan)
+}
+
+// matchPatterns applies include and exclude patterns (path.Match syntax) to a name.
+// An empty include list accepts everything that isn't excluded.
+func matchPatterns(name string, include, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// delta returns how much a cumulative counter grew, treating a counter that went
+// backwards (reset or wrapped) as no activity
+func delta(prev, cur uint64) float64 {
+	if cur < prev {
+		return 0
+	}
+	return float64(cur - prev)
+}
+
+// rate turns two readings of a cumulative counter into a per-second rate
+func rate(prev, cur uint64, elapsed float64) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return delta(prev, cur) / elapsed
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"net/netip"
+	"sort"
+	"time"
+)
+
+// ownerRescanInterval bounds how stale the socket owners can get when no new sockets appear,
+// e.g. after a process hands its socket to a child and exits
+const ownerRescanInterval = 10 * time.Second
+
+// Connection is one TCP or UDP socket from the kernel's socket tables
+type Connection struct {
+	Protocol   string // "tcp", "tcp6", "udp" or "udp6"
+	LocalAddr  netip.AddrPort
+	RemoteAddr netip.AddrPort // unspecified for listening and unconnected sockets
+	State      string         // TCP state such as "ESTABLISHED" or "TIME_WAIT"; UDP is "UNCONN" or "ESTABLISHED"
+	UID        uint32
+	Inode      uint64 // 0 for sockets no longer attached to a file, such as TIME_WAIT
+
+	// The process holding the socket open, when it could be found; finding other users'
+	// processes needs root. A socket shared by several processes names the lowest PID.
+	PID     int32
+	Command string
+}
+
+// socketOwner is the process a socket inode was found open in
+type socketOwner struct {
+	pid     int32
+	command string
+}
+
+// connectionCollector lists sockets and who owns them. Finding owners means reading every
+// process's file descriptors, so that is only redone when a socket shows up that the last
+// scan didn't see, or every ownerRescanInterval.
+type connectionCollector struct {
+	owners   map[uint64]socketOwner
+	unowned  map[uint64]bool // inodes the last scan couldn't place, so they don't force another
+	lastScan time.Time
+}
+
+// NewConnectionCollector returns a collector for the system's TCP and UDP sockets
+func NewConnectionCollector() Collector {
+	return &connectionCollector{}
+}
+
+func (c *connectionCollector) Name() string { return "connections" }
+
+func (c *connectionCollector) Collect(m *Metrics) error {
+	conns, err := readConnections()
+	if err != nil {
+		m.Connections = nil
+		return err
+	}
+
+	rescan := time.Since(c.lastScan) >= ownerRescanInterval
+	for _, conn := range conns {
+		if rescan {
+			break
+		}
+		_, known := c.owners[conn.Inode]
+		rescan = conn.Inode != 0 && !known && !c.unowned[conn.Inode]
+	}
+	if rescan {
+		c.owners = readSocketOwners()
+		c.unowned = make(map[uint64]bool)
+		c.lastScan = time.Now()
+		for _, conn := range conns {
+			if _, known := c.owners[conn.Inode]; !known && conn.Inode != 0 {
+				c.unowned[conn.Inode] = true
+			}
+		}
+	}
+
+	for i := range conns {
+		if owner, ok := c.owners[conns[i].Inode]; ok && conns[i].Inode != 0 {
+			conns[i].PID, conns[i].Command = owner.pid, owner.command
+		}
+	}
+	m.Connections = conns
+	return nil
+}
+
+// ConnectionStateCount is how many sockets are in one state
+type ConnectionStateCount struct {
+	State string
+	Count int
+}
+
+// CountConnectionStates tallies sockets by state, most common first
+func CountConnectionStates(conns []Connection) []ConnectionStateCount {
+	index := make(map[string]int)
+	var counts []ConnectionStateCount
+	for _, conn := range conns {
+		i, ok := index[conn.State]
+		if !ok {
+			i = len(counts)
+			index[conn.State] = i
+			counts = append(counts, ConnectionStateCount{State: conn.State})
+		}
+		counts[i].Count++
+	}
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].State < counts[j].State
+	})
+	return counts
+}
+
+// portRange is an inclusive range of port numbers
+type portRange struct {
+	first, last uint16
+}
+
+func (r portRange) contains(port uint16) bool {
+	return port >= r.first && port <= r.last
+}
+
+// Listeners picks the listening sockets out of a connection list: TCP sockets in LISTEN and
+// UDP sockets bound to a port without a peer. A UDP socket on the wildcard address with a port
+// from the ephemeral range is a client's, such as a resolver's or a QUIC client's, and is left
+// out. A port shared by several sockets (SO_REUSEPORT, or one per worker) is listed once, with
+// the lowest owning PID.
+func Listeners(conns []Connection) []Connection {
+	return listeners(conns, ephemeralPorts())
+}
+
+func listeners(conns []Connection, ephemeral portRange) []Connection {
+	index := make(map[string]int)
+	var listeners []Connection
+	for _, conn := range conns {
+		if (conn.State != "LISTEN" && conn.State != "UNCONN") || conn.LocalAddr.Port() == 0 {
+			continue
+		}
+		if conn.State == "UNCONN" && conn.LocalAddr.Addr().IsUnspecified() && ephemeral.contains(conn.LocalAddr.Port()) {
+			continue
+		}
+		key := conn.Protocol + " " + conn.LocalAddr.String()
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(listeners)
+			listeners = append(listeners, conn)
+			continue
+		}
+		if conn.PID != 0 && (listeners[i].PID == 0 || conn.PID < listeners[i].PID) {
+			listeners[i] = conn
+		}
+	}
+	return listeners
+}
@@ -0,0 +1,191 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tcpStates are the kernel's TCP state numbers, as they appear in /proc/net/tcp
+var tcpStates = map[uint64]string{
+	0x01: "ESTABLISHED",
+	0x02: "SYN_SENT",
+	0x03: "SYN_RECV",
+	0x04: "FIN_WAIT1",
+	0x05: "FIN_WAIT2",
+	0x06: "TIME_WAIT",
+	0x07: "CLOSE",
+	0x08: "CLOSE_WAIT",
+	0x09: "LAST_ACK",
+	0x0A: "LISTEN",
+	0x0B: "CLOSING",
+	0x0C: "NEW_SYN_RECV",
+}
+
+// socketTables are the /proc/net files connections are read from
+var socketTables = []string{"tcp", "tcp6", "udp", "udp6"}
+
+// ephemeralPorts reads the range the kernel picks client ports from, falling back to its default
+func ephemeralPorts() portRange {
+	ports := portRange{32768, 60999}
+	data, err := os.ReadFile("/proc/sys/net/ipv4/ip_local_port_range")
+	if err != nil {
+		return ports
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return ports
+	}
+	first, err1 := strconv.ParseUint(fields[0], 10, 16)
+	last, err2 := strconv.ParseUint(fields[1], 10, 16)
+	if err1 != nil || err2 != nil || first > last {
+		return ports
+	}
+	return portRange{uint16(first), uint16(last)}
+}
+
+func readConnections() ([]Connection, error) {
+	var conns []Connection
+	found := false
+	for _, protocol := range socketTables {
+		table, err := readSocketTable(filepath.Join("/proc/net", protocol), protocol)
+		if err != nil {
+			// tcp6 and udp6 are missing when IPv6 is disabled
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		found = true
+		conns = append(conns, table...)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: no socket tables in /proc/net", ErrUnavailable)
+	}
+	return conns, nil
+}
+
+// readSocketTable parses one /proc/net/{tcp,udp}[6] file, whose lines look like
+// "0: 0100007F:0277 00000000:0000 0A 00000000:00000000 00:00000000 00000000 0 0 12345 ..."
+func readSocketTable(path, protocol string) ([]Connection, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var conns []Connection
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		local, err1 := parseSocketAddr(fields[1])
+		remote, err2 := parseSocketAddr(fields[2])
+		state, err3 := strconv.ParseUint(fields[3], 16, 8)
+		uid, err4 := strconv.ParseUint(fields[7], 10, 32)
+		inode, err5 := strconv.ParseUint(fields[9], 10, 64)
+		if err := errors.Join(err1, err2, err3, err4, err5); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		conns = append(conns, Connection{
+			Protocol:   protocol,
+			LocalAddr:  local,
+			RemoteAddr: remote,
+			State:      socketState(protocol, state),
+			UID:        uint32(uid),
+			Inode:      inode,
+		})
+	}
+	return conns, scanner.Err()
+}
+
+// socketState names a state number; UDP sockets reuse the TCP numbers, but only "connected"
+// (ESTABLISHED) and "not connected" (CLOSE) mean anything for them
+func socketState(protocol string, state uint64) string {
+	if strings.HasPrefix(protocol, "udp") {
+		if state == 0x01 {
+			return "ESTABLISHED"
+		}
+		return "UNCONN"
+	}
+	if name, ok := tcpStates[state]; ok {
+		return name
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", state)
+}
+
+// parseSocketAddr decodes "0100007F:0035": the address is printed as 32-bit words in host byte
+// order, so 127.0.0.1 comes out backwards on little-endian machines, and the port is plain hex
+func parseSocketAddr(s string) (netip.AddrPort, error) {
+	addrHex, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return netip.AddrPort{}, fmt.Errorf("bad socket address %q", s)
+	}
+	raw, err := hex.DecodeString(addrHex)
+	if err != nil || (len(raw) != 4 && len(raw) != 16) {
+		return netip.AddrPort{}, fmt.Errorf("bad socket address %q", s)
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return netip.AddrPort{}, fmt.Errorf("bad socket port %q", s)
+	}
+	for i := 0; i < len(raw); i += 4 {
+		binary.NativeEndian.PutUint32(raw[i:], binary.BigEndian.Uint32(raw[i:]))
+	}
+	addr, _ := netip.AddrFromSlice(raw)
+	return netip.AddrPortFrom(addr.Unmap(), uint16(port)), nil
+}
+
+// readSocketOwners maps socket inodes to the process holding them, from the "socket:[inode]"
+// links in /proc/<pid>/fd. Processes we can't look into are skipped.
+func readSocketOwners() map[uint64]socketOwner {
+	owners := make(map[uint64]socketOwner)
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return owners
+	}
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		var command string
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(target, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]"), 10, 64)
+			if err != nil {
+				continue
+			}
+			if owner, taken := owners[inode]; taken && owner.pid < int32(pid) {
+				continue
+			}
+			if command == "" {
+				comm, _ := os.ReadFile(filepath.Join("/proc", entry.Name(), "comm"))
+				command = strings.TrimSpace(string(comm))
+			}
+			owners[inode] = socketOwner{pid: int32(pid), command: command}
+		}
+	}
+	return owners
+}
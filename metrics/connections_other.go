@@ -0,0 +1,19 @@
+//go:build !linux
+
+package metrics
+
+import "fmt"
+
+// readConnections needs /proc/net, which only Linux has
+func readConnections() ([]Connection, error) {
+	return nil, fmt.Errorf("%w: socket tables are read from /proc/net, Linux only", ErrUnavailable)
+}
+
+func readSocketOwners() map[uint64]socketOwner {
+	return nil
+}
+
+// ephemeralPorts is the IANA range client ports come from on most other systems
+func ephemeralPorts() portRange {
+	return portRange{49152, 65535}
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"net/netip"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestListeners(t *testing.T) {
+	conn := func(protocol, local, state string, pid int32) Connection {
+		remote := "0.0.0.0:0"
+		if protocol == "tcp6" || protocol == "udp6" {
+			remote = "[::]:0"
+		}
+		return Connection{
+			Protocol:   protocol,
+			LocalAddr:  netip.MustParseAddrPort(local),
+			RemoteAddr: netip.MustParseAddrPort(remote),
+			State:      state,
+			PID:        pid,
+		}
+	}
+	conns := []Connection{
+		conn("tcp", "0.0.0.0:22", "LISTEN", 100),
+		conn("tcp", "127.0.0.1:5432", "LISTEN", 200),
+		conn("tcp", "10.0.0.5:22", "ESTABLISHED", 300),
+		conn("tcp", "0.0.0.0:45000", "LISTEN", 400), // TCP listeners count wherever their port is
+		conn("tcp6", "[::]:80", "LISTEN", 0),
+		conn("tcp6", "[::]:80", "LISTEN", 520),
+		conn("tcp6", "[::]:80", "LISTEN", 510),
+		conn("udp", "0.0.0.0:53", "UNCONN", 600),
+		conn("udp", "0.0.0.0:51234", "UNCONN", 700),        // a client socket, e.g. a resolver's
+		conn("udp6", "[::]:40000", "UNCONN", 800),          // a QUIC client's
+		conn("udp", "127.0.0.1:41000", "UNCONN", 900),      // bound to an address on purpose
+		conn("udp", "0.0.0.0:61000", "UNCONN", 1000),       // above the ephemeral range
+		conn("udp", "192.168.1.2:50000", "ESTABLISHED", 0), // connected, so not listening
+		conn("udp", "0.0.0.0:0", "UNCONN", 0),
+	}
+
+	var got []string
+	for _, c := range listeners(conns, portRange{32768, 60999}) {
+		got = append(got, c.Protocol+" "+c.LocalAddr.String()+" "+strconv.Itoa(int(c.PID)))
+	}
+	want := []string{
+		"tcp 0.0.0.0:22 100",
+		"tcp 127.0.0.1:5432 200",
+		"tcp 0.0.0.0:45000 400",
+		"tcp6 [::]:80 510",
+		"udp 0.0.0.0:53 600",
+		"udp 127.0.0.1:41000 900",
+		"udp 0.0.0.0:61000 1000",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("listeners =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestCountConnectionStates(t *testing.T) {
+	conns := []Connection{
+		{State: "ESTABLISHED"}, {State: "LISTEN"}, {State: "ESTABLISHED"},
+		{State: "TIME_WAIT"}, {State: "LISTEN"}, {State: "ESTABLISHED"},
+	}
+	want := []ConnectionStateCount{{"ESTABLISHED", 3}, {"LISTEN", 2}, {"TIME_WAIT", 1}}
+	if got := CountConnectionStates(conns); !reflect.DeepEqual(got, want) {
+		t.Errorf("CountConnectionStates = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+)
+
+// LimitsMode says what the CPU and memory percentages are measured against
+type LimitsMode string
+
+const (
+	// LimitsAuto uses the enclosing cgroup's limits when running in a container, the host otherwise
+	LimitsAuto LimitsMode = "auto"
+	// LimitsHost always uses the host's CPUs and RAM
+	LimitsHost LimitsMode = "host"
+	// LimitsCgroup uses the enclosing cgroup's limits wherever they are set, container or not
+	LimitsCgroup LimitsMode = "cgroup"
+)
+
+// ParseLimitsMode checks a -limits flag value
+func ParseLimitsMode(value string) (LimitsMode, error) {
+	switch mode := LimitsMode(value); mode {
+	case LimitsAuto, LimitsHost, LimitsCgroup:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown limits mode %q; use auto, host or cgroup", value)
+}
+
+// ContainerInfo describes the cgroup the monitor itself runs in
+type ContainerInfo struct {
+	Containerised bool
+	Runtime       string  // "docker", "podman", "kubernetes", "lxc"... or "" when not known
+	Cgroup        string  // path of the enclosing cgroup
+	CPULimit      float64 // CPUs allowed by the quota, 0 when unlimited
+	MemoryLimit   uint64  // bytes, 0 when unlimited
+
+	// Set when CPUUsage or MemoryUsage (and MemoryTotal/MemoryAvailable) are relative to the
+	// limits above rather than to the host
+	CPUFromLimit    bool
+	MemoryFromLimit bool
+}
+
+// enclosingCgroup is what the platform code reads about the monitor's own cgroup
+type enclosingCgroup struct {
+	info ContainerInfo
+
+	cpuUsage      uint64 // cumulative CPU time of the cgroup, in nanoseconds
+	memoryCurrent uint64 // working set: usage less inactive page cache, as docker stats reports it
+}
+
+// containerCollector detects a container and, depending on its mode, rescales the host-wide
+// CPU and memory figures to the cgroup's limits. It must run after the cpu and memory collectors.
+type containerCollector struct {
+	mode      LimitsMode
+	root      string
+	prevUsage uint64
+	prevTime  time.Time
+}
+
+// NewContainerCollector returns a collector that fills Metrics.Container, applying limits per
+// mode. root is where the cgroup hierarchy is mounted, as for NewCgroupCollector; empty means
+// /sys/fs/cgroup.
+func NewContainerCollector(mode LimitsMode, root string) Collector {
+	return &containerCollector{mode: mode, root: root}
+}
+
+func (c *containerCollector) Name() string { return "container" }
+
+func (c *containerCollector) Collect(m *Metrics) error {
+	cg, err := readEnclosingCgroup(c.root)
+	if err != nil {
+		m.Container = ContainerInfo{}
+		return err
+	}
+	info := cg.info
+
+	now := time.Now()
+	var elapsed float64
+	if !c.prevTime.IsZero() {
+		elapsed = now.Sub(c.prevTime).Seconds()
+	}
+	c.prevTime = now
+	prevUsage := c.prevUsage
+	c.prevUsage = cg.cpuUsage
+
+	apply := c.mode == LimitsCgroup || (c.mode == LimitsAuto && info.Containerised)
+	if apply && info.CPULimit > 0 && elapsed > 0 {
+		seconds := delta(prevUsage, cg.cpuUsage) / 1e9
+		m.CPUUsage = min(seconds/elapsed/info.CPULimit*100, 100)
+		info.CPUFromLimit = true
+	}
+	if apply && info.MemoryLimit > 0 {
+		used := min(cg.memoryCurrent, info.MemoryLimit)
+		m.MemoryTotal = info.MemoryLimit
+		m.MemoryAvailable = info.MemoryLimit - used
+		m.MemoryUsage = float64(used) / float64(info.MemoryLimit) * 100
+		info.MemoryFromLimit = true
+	}
+
+	m.Container = info
+	return nil
+}
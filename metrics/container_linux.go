@@ -0,0 +1,156 @@
+//go:build linux
+
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// v1LimitUnset is the smallest value cgroup v1 uses to mean "no memory limit"; the exact
+// number depends on the page size
+const v1LimitUnset = 1 << 62
+
+// containerMarkers map words in a cgroup path to the runtime that creates such cgroups
+var containerMarkers = []struct{ marker, runtime string }{
+	{"kubepods", "kubernetes"},
+	{"libpod", "podman"},
+	{"docker", "docker"},
+	{"containerd", "containerd"},
+	{"lxc", "lxc"},
+}
+
+// readEnclosingCgroup finds the cgroup this process runs in, its tightest CPU and memory limits
+// (a parent's limit applies to everything below it) and its current usage. root is a cgroup v2
+// hierarchy, or holds the v1 controller mounts, and defaults to /sys/fs/cgroup.
+func readEnclosingCgroup(root string) (enclosingCgroup, error) {
+	if root == "" {
+		root = defaultCgroupRoot
+	}
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return enclosingCgroup{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	paths := make(map[string]string) // controller -> path; "" is the cgroup v2 entry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			paths[controller] = parts[2]
+		}
+	}
+
+	var cg enclosingCgroup
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		cg = readEnclosingV2(root, paths[""])
+	} else {
+		cg = readEnclosingV1(root, paths)
+	}
+	cg.info.Containerised, cg.info.Runtime = detectContainer(cg.info.Cgroup)
+	return cg, nil
+}
+
+// cgroupDir joins a cgroup path onto its mount, falling back to the mount itself when the path
+// isn't visible there, as inside a container with its own cgroup namespace
+func cgroupDir(mount, path string) string {
+	dir := filepath.Join(mount, path)
+	if _, err := os.Stat(dir); err != nil {
+		return mount
+	}
+	return dir
+}
+
+// walkUp calls visit for dir and each of its parents up to and including mount
+func walkUp(dir, mount string, visit func(dir string)) {
+	for {
+		visit(dir)
+		if dir == mount || !strings.HasPrefix(dir, mount) {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func readEnclosingV2(root, path string) enclosingCgroup {
+	cg := enclosingCgroup{info: ContainerInfo{Cgroup: path}}
+	dir := cgroupDir(root, path)
+	walkUp(dir, root, func(d string) {
+		if cpus := readCPUMax(filepath.Join(d, "cpu.max")); cpus > 0 && (cg.info.CPULimit == 0 || cpus < cg.info.CPULimit) {
+			cg.info.CPULimit = cpus
+		}
+		if limit, ok := readCgroupValue(filepath.Join(d, "memory.max")); ok && limit != Unlimited && (cg.info.MemoryLimit == 0 || limit < cg.info.MemoryLimit) {
+			cg.info.MemoryLimit = limit
+		}
+	})
+
+	cg.cpuUsage = readKeyValueFile(filepath.Join(dir, "cpu.stat"))["usage_usec"] * 1000
+	current, _ := readCgroupValue(filepath.Join(dir, "memory.current"))
+	inactive := readKeyValueFile(filepath.Join(dir, "memory.stat"))["inactive_file"]
+	cg.memoryCurrent = uint64(delta(inactive, current))
+	return cg
+}
+
+func readEnclosingV1(root string, paths map[string]string) enclosingCgroup {
+	cg := enclosingCgroup{info: ContainerInfo{Cgroup: paths["memory"]}}
+
+	cpuMount := filepath.Join(root, "cpu")
+	cpuDir := cgroupDir(cpuMount, paths["cpu"])
+	walkUp(cpuDir, cpuMount, func(d string) {
+		quota, okQuota := readCgroupValue(filepath.Join(d, "cpu.cfs_quota_us"))
+		period, okPeriod := readCgroupValue(filepath.Join(d, "cpu.cfs_period_us"))
+		// An unlimited quota is -1, which doesn't parse as unsigned
+		if !okQuota || !okPeriod || period == 0 {
+			return
+		}
+		if cpus := float64(quota) / float64(period); cg.info.CPULimit == 0 || cpus < cg.info.CPULimit {
+			cg.info.CPULimit = cpus
+		}
+	})
+
+	memoryMount := filepath.Join(root, "memory")
+	memoryDir := cgroupDir(memoryMount, paths["memory"])
+	walkUp(memoryDir, memoryMount, func(d string) {
+		limit, ok := readCgroupValue(filepath.Join(d, "memory.limit_in_bytes"))
+		if ok && limit < v1LimitUnset && (cg.info.MemoryLimit == 0 || limit < cg.info.MemoryLimit) {
+			cg.info.MemoryLimit = limit
+		}
+	})
+
+	cpuacctMount := filepath.Join(root, "cpuacct")
+	cg.cpuUsage, _ = readCgroupValue(filepath.Join(cgroupDir(cpuacctMount, paths["cpuacct"]), "cpuacct.usage"))
+	usage, _ := readCgroupValue(filepath.Join(memoryDir, "memory.usage_in_bytes"))
+	inactive := readKeyValueFile(filepath.Join(memoryDir, "memory.stat"))["total_inactive_file"]
+	cg.memoryCurrent = uint64(delta(inactive, usage))
+	return cg
+}
+
+// detectContainer looks for the marks container runtimes leave behind
+func detectContainer(cgroupPath string) (bool, string) {
+	if _, err := os.Stat("/.dockerenv"); err == nil {
+		return true, "docker"
+	}
+	if _, err := os.Stat("/run/.containerenv"); err == nil {
+		return true, "podman"
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true, "kubernetes"
+	}
+	for _, m := range containerMarkers {
+		if strings.Contains(cgroupPath, m.marker) {
+			return true, m.runtime
+		}
+	}
+	// systemd-nspawn, lxc and others set container= in PID 1's environment
+	if environ, err := os.ReadFile("/proc/1/environ"); err == nil {
+		for _, entry := range strings.Split(string(environ), "\x00") {
+			if runtime, ok := strings.CutPrefix(entry, "container="); ok && runtime != "" {
+				return true, runtime
+			}
+		}
+	}
+	return false, ""
+}
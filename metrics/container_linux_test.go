@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// The container fixture is a cgroup v2 hierarchy as seen from inside a container with its own
+// cgroup namespace: the container's limits sit at the root. It allows 2 CPUs and 4 GiB, of
+// which 1 GiB is in use, 256 MiB of it inactive page cache.
+func TestContainerCollectorLimits(t *testing.T) {
+	root := copyTree(t, filepath.Join("testdata", "container"))
+	c := NewContainerCollector(LimitsCgroup, root).(*containerCollector)
+
+	m := Metrics{CPUUsage: 10, MemoryUsage: 5, MemoryTotal: 64 << 30, MemoryAvailable: 60 << 30}
+	if err := c.Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if m.Container.CPULimit != 2 || m.Container.MemoryLimit != 4<<30 {
+		t.Errorf("limits = %v CPUs, %d bytes, want 2 CPUs and 4 GiB", m.Container.CPULimit, m.Container.MemoryLimit)
+	}
+	if !m.Container.MemoryFromLimit || m.MemoryTotal != 4<<30 || m.MemoryAvailable != 4<<30-768<<20 {
+		t.Errorf("memory = %d total, %d available, want 4 GiB and 3.25 GiB from the limit", m.MemoryTotal, m.MemoryAvailable)
+	}
+	if m.MemoryUsage != 18.75 {
+		t.Errorf("memory usage = %v%%, want 18.75%% (768 MiB working set of 4 GiB)", m.MemoryUsage)
+	}
+	if m.Container.CPUFromLimit || m.CPUUsage != 10 {
+		t.Errorf("first sample set CPU to %v%% from the limit, want the host figure left alone", m.CPUUsage)
+	}
+
+	// One CPU second used over a second is half of the 2 CPUs allowed
+	c.prevTime = c.prevTime.Add(-time.Second)
+	writeFixture(t, filepath.Join(root, "cpu.stat"), "usage_usec 11000000\n")
+	if err := c.Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if !m.Container.CPUFromLimit || math.Abs(m.CPUUsage-50) > 2.5 {
+		t.Errorf("CPU = %.2f%% (from limit %v), want about 50%% of the limit", m.CPUUsage, m.Container.CPUFromLimit)
+	}
+}
+
+func TestContainerCollectorHostMode(t *testing.T) {
+	c := NewContainerCollector(LimitsHost, filepath.Join("testdata", "container"))
+	m := Metrics{CPUUsage: 10, MemoryUsage: 5, MemoryTotal: 64 << 30}
+	if err := c.Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if m.Container.CPULimit != 2 || m.Container.MemoryLimit != 4<<30 {
+		t.Errorf("limits = %v CPUs, %d bytes, want them reported even in host mode", m.Container.CPULimit, m.Container.MemoryLimit)
+	}
+	if m.MemoryTotal != 64<<30 || m.MemoryUsage != 5 || m.Container.MemoryFromLimit {
+		t.Errorf("host mode rescaled memory to %d bytes, %v%%", m.MemoryTotal, m.MemoryUsage)
+	}
+}
+
+// In the cgroup fixture nginx.service allows half a CPU and 256 MiB under a system.slice
+// without limits; the tightest limit on the way up applies
+func TestReadEnclosingV2(t *testing.T) {
+	root := filepath.Join("testdata", "cgroup")
+	cg := readEnclosingV2(root, "/system.slice/nginx.service")
+	if cg.info.CPULimit != 0.5 || cg.info.MemoryLimit != 256<<20 {
+		t.Errorf("nginx limits = %v CPUs, %d bytes, want 0.5 and 256 MiB", cg.info.CPULimit, cg.info.MemoryLimit)
+	}
+	if cg.cpuUsage != 2000000*1000 || cg.memoryCurrent != 100<<20 {
+		t.Errorf("nginx usage = %d ns, %d bytes, want 2s and 100 MiB", cg.cpuUsage, cg.memoryCurrent)
+	}
+
+	cg = readEnclosingV2(root, "/user.slice")
+	if cg.info.CPULimit != 0 || cg.info.MemoryLimit != 0 {
+		t.Errorf("user.slice limits = %v CPUs, %d bytes, want none", cg.info.CPULimit, cg.info.MemoryLimit)
+	}
+}
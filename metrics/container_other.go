@@ -0,0 +1,10 @@
+//go:build !linux
+
+package metrics
+
+import "fmt"
+
+// readEnclosingCgroup needs cgroups, which only Linux has
+func readEnclosingCgroup(root string) (enclosingCgroup, error) {
+	return enclosingCgroup{}, fmt.Errorf("%w: cgroups are Linux only", ErrUnavailable)
+}
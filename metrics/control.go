@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// ProcessSignal is a signal that can be sent from the dashboard
+type ProcessSignal struct {
+	Name   string
+	Number int
+}
+
+// IOPriorityClass is a Linux I/O scheduling class, as used by ionice
+type IOPriorityClass int
+
+const (
+	IOPriorityRealtime   IOPriorityClass = 1
+	IOPriorityBestEffort IOPriorityClass = 2
+	IOPriorityIdle       IOPriorityClass = 3
+)
+
+// controlError turns a raw errno from a process control call into a message that says what went wrong
+func controlError(action string, pid int32, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, syscall.EPERM), errors.Is(err, syscall.EACCES):
+		return fmt.Errorf("not permitted to %s PID %d: it belongs to another user or needs root", action, pid)
+	case errors.Is(err, syscall.ESRCH):
+		return fmt.Errorf("cannot %s PID %d: the process no longer exists", action, pid)
+	case errors.Is(err, syscall.EINVAL):
+		return fmt.Errorf("cannot %s PID %d: invalid value", action, pid)
+	}
+	return fmt.Errorf("%s PID %d: %w", action, pid, err)
+}
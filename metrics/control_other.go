@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package metrics
+
+import "fmt"
+
+// ProcessSignals is empty where Unix signals don't exist
+var ProcessSignals []ProcessSignal
+
+// SignalProcess is not supported on this platform
+func SignalProcess(pid int32, sig int) error {
+	return fmt.Errorf("%w: signals are not supported on this platform", ErrUnavailable)
+}
+
+// SetNice is not supported on this platform
+func SetNice(pid int32, nice int) error {
+	return fmt.Errorf("%w: nice values are not supported on this platform", ErrUnavailable)
+}
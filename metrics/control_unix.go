@@ -0,0 +1,33 @@
+//go:build linux || darwin
+
+package metrics
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// ProcessSignals lists the signals offered when signalling a process, most common first
+var ProcessSignals = []ProcessSignal{
+	{"SIGTERM", int(syscall.SIGTERM)},
+	{"SIGKILL", int(syscall.SIGKILL)},
+	{"SIGSTOP", int(syscall.SIGSTOP)},
+	{"SIGCONT", int(syscall.SIGCONT)},
+	{"SIGHUP", int(syscall.SIGHUP)},
+	{"SIGINT", int(syscall.SIGINT)},
+	{"SIGUSR1", int(syscall.SIGUSR1)},
+	{"SIGUSR2", int(syscall.SIGUSR2)},
+}
+
+// SignalProcess sends signal number sig to a process
+func SignalProcess(pid int32, sig int) error {
+	err := syscall.Kill(int(pid), syscall.Signal(sig))
+	return controlError(fmt.Sprintf("send signal %d to", sig), pid, err)
+}
+
+// SetNice changes the scheduling priority of a process (-20 highest to 19 lowest).
+// Raising priority normally needs root.
+func SetNice(pid int32, nice int) error {
+	err := syscall.Setpriority(syscall.PRIO_PROCESS, int(pid), nice)
+	return controlError("renice", pid, err)
+}
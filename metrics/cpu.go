@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// CPUTimeShares splits CPU time since the previous sample by where it went, in percent of
+// all CPU time. Guest time is reported on its own rather than inside User and Nice.
+type CPUTimeShares struct {
+	User    float64
+	Nice    float64
+	System  float64
+	IOWait  float64
+	IRQ     float64
+	SoftIRQ float64
+	Steal   float64
+	Guest   float64
+	Idle    float64
+}
+
+// cpuCollector reports aggregate and per-logical-CPU utilisation, plus a breakdown of CPU time
+type cpuCollector struct {
+	prevTimes *cpu.TimesStat
+}
+
+func (c *cpuCollector) Name() string { return "cpu" }
+
+func (c *cpuCollector) Collect(m *Metrics) error {
+	cpuPercent, err := cpu.Percent(0, false)
+	if err != nil {
+		return fmt.Errorf("cpu percent: %w", err)
+	}
+	if len(cpuPercent) == 0 {
+		return errors.New("cpu percent: no readings returned")
+	}
+	m.CPUUsage = cpuPercent[0]
+
+	perCore, err := cpu.Percent(0, true)
+	if err != nil {
+		return fmt.Errorf("per-core cpu percent: %w", err)
+	}
+	m.CPUPerCore = perCore
+
+	times, err := cpu.Times(false)
+	if err != nil {
+		return fmt.Errorf("cpu times: %w", err)
+	}
+	if len(times) == 0 {
+		return errors.New("cpu times: no readings returned")
+	}
+	if c.prevTimes != nil {
+		m.CPUBreakdown = timeShares(*c.prevTimes, times[0])
+	}
+	c.prevTimes = &times[0]
+	return nil
+}
+
+// timeShares converts the difference between two cumulative CPU time readings into shares
+func timeShares(prev, cur cpu.TimesStat) CPUTimeShares {
+	user := cur.User - prev.User
+	nice := cur.Nice - prev.Nice
+	guest := (cur.Guest - prev.Guest) + (cur.GuestNice - prev.GuestNice)
+	if runtime.GOOS == "linux" {
+		// The kernel already counts guest time inside user and nice
+		user -= cur.Guest - prev.Guest
+		nice -= cur.GuestNice - prev.GuestNice
+	}
+
+	shares := CPUTimeShares{
+		User:    user,
+		Nice:    nice,
+		System:  cur.System - prev.System,
+		IOWait:  cur.Iowait - prev.Iowait,
+		IRQ:     cur.Irq - prev.Irq,
+		SoftIRQ: cur.Softirq - prev.Softirq,
+		Steal:   cur.Steal - prev.Steal,
+		Guest:   guest,
+		Idle:    cur.Idle - prev.Idle,
+	}
+	total := shares.User + shares.Nice + shares.System + shares.IOWait + shares.IRQ +
+		shares.SoftIRQ + shares.Steal + shares.Guest + shares.Idle
+	if total <= 0 {
+		return CPUTimeShares{}
+	}
+
+	percent := func(v float64) float64 {
+		if v < 0 {
+			return 0
+		}
+		return v / total * 100
+	}
+	return CPUTimeShares{
+		User:    percent(shares.User),
+		Nice:    percent(shares.Nice),
+		System:  percent(shares.System),
+		IOWait:  percent(shares.IOWait),
+		IRQ:     percent(shares.IRQ),
+		SoftIRQ: percent(shares.SoftIRQ),
+		Steal:   percent(shares.Steal),
+		Guest:   percent(shares.Guest),
+		Idle:    percent(shares.Idle),
+	}
+}
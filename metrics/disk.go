@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// FilesystemStats is the space and inode usage of one mounted filesystem
+type FilesystemStats struct {
+	Mountpoint    string
+	Device        string
+	Fstype        string
+	Total         uint64
+	Used          uint64
+	Free          uint64
+	UsedPercent   float64
+	InodesTotal   uint64
+	InodesUsed    uint64
+	InodesPercent float64
+}
+
+// pseudoFilesystems hold no user data and are never listed, even if the platform reports them
+var pseudoFilesystems = map[string]bool{
+	"tmpfs": true, "devtmpfs": true, "devfs": true, "proc": true, "sysfs": true,
+	"cgroup": true, "cgroup2": true, "overlay": true, "squashfs": true, "autofs": true,
+	"debugfs": true, "tracefs": true, "securityfs": true, "pstore": true, "bpf": true,
+	"configfs": true, "fusectl": true, "mqueue": true, "hugetlbfs": true, "devpts": true,
+	"binfmt_misc": true, "nsfs": true, "ramfs": true, "rpc_pipefs": true, "efivarfs": true,
+}
+
+// filesystemCollector reports usage of every real mounted filesystem; DiskUsage is that of "/",
+// or 0 when "/" is excluded or can't be read
+type filesystemCollector struct {
+	include, exclude []string
+}
+
+// NewFilesystemCollector returns the filesystem collector restricted to mount points matching
+// the include patterns (all if empty) and none of the exclude patterns, e.g. "/snap/*".
+// Patterns use path.Match syntax.
+func NewFilesystemCollector(include, exclude []string) Collector {
+	return &filesystemCollector{include: include, exclude: exclude}
+}
+
+func (c *filesystemCollector) Name() string { return "filesystem" }
+
+func (c *filesystemCollector) Collect(m *Metrics) error {
+	partitions, err := disk.Partitions(false)
+	if err != nil && len(partitions) == 0 {
+		return fmt.Errorf("disk partitions: %w", err)
+	}
+
+	m.DiskUsage = 0
+	var filesystems []FilesystemStats
+	var failed []string
+	for _, p := range selectPartitions(partitions, readSubvolumes(), isFile, c.include, c.exclude) {
+		usage, err := disk.Usage(p.Mountpoint)
+		if err != nil {
+			failed = append(failed, p.Mountpoint)
+			continue
+		}
+		filesystems = append(filesystems, FilesystemStats{
+			Mountpoint:    p.Mountpoint,
+			Device:        p.Device,
+			Fstype:        p.Fstype,
+			Total:         usage.Total,
+			Used:          usage.Used,
+			Free:          usage.Free,
+			UsedPercent:   usage.UsedPercent,
+			InodesTotal:   usage.InodesTotal,
+			InodesUsed:    usage.InodesUsed,
+			InodesPercent: usage.InodesUsedPercent,
+		})
+		if p.Mountpoint == "/" {
+			m.DiskUsage = usage.UsedPercent
+		}
+	}
+	sort.Slice(filesystems, func(i, j int) bool { return filesystems[i].Mountpoint < filesystems[j].Mountpoint })
+	m.Filesystems = filesystems
+
+	if len(failed) > 0 {
+		return fmt.Errorf("disk usage failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// selectPartitions picks the mounts worth listing: real filesystems matching the patterns, each
+// once. A device mounted in several places (bind mounts, container volumes) is listed at its
+// first mount point, but btrfs subvolumes, which share a device, are told apart by the subvol
+// each mount point shows. Files bind-mounted over files, such as a container's /etc/hosts,
+// aren't filesystems of their own and are skipped. "/" always comes first and is kept whatever
+// its type, since in a container it is an overlay.
+func selectPartitions(partitions []disk.PartitionStat, subvolumes map[string]string, isFile func(path string) bool, include, exclude []string) []disk.PartitionStat {
+	partitions = append([]disk.PartitionStat(nil), partitions...)
+	sort.SliceStable(partitions, func(i, j int) bool {
+		return partitions[i].Mountpoint == "/" && partitions[j].Mountpoint != "/"
+	})
+
+	var selected []disk.PartitionStat
+	seenMounts := make(map[string]bool)
+	seenDevices := make(map[string]bool)
+	for _, p := range partitions {
+		if (pseudoFilesystems[p.Fstype] && p.Mountpoint != "/") || !matchPatterns(p.Mountpoint, include, exclude) {
+			continue
+		}
+		if p.Mountpoint != "/" && isFile(p.Mountpoint) {
+			continue
+		}
+		device := p.Device + "\x00" + subvolumes[p.Mountpoint]
+		if seenMounts[p.Mountpoint] || seenDevices[device] {
+			continue
+		}
+		seenMounts[p.Mountpoint], seenDevices[device] = true, true
+		selected = append(selected, p)
+	}
+	return selected
+}
+
+// isFile reports whether a mount point is a file rather than a directory; one that can't be
+// looked at counts as a directory, leaving disk.Usage to report the problem
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// DiskDeviceStats holds iostat -x style figures for one whole block device
+type DiskDeviceStats struct {
+	Name        string
+	ReadMBps    float64
+	WriteMBps   float64
+	ReadIOPS    float64
+	WriteIOPS   float64
+	AwaitMs     float64 // average time a request spent queued and being served
+	QueueDepth  float64 // average number of requests in flight
+	UtilPercent float64 // share of time the device was busy
+	Stacked     bool    // built on other listed devices (LVM, md RAID), so left out of totals
+}
+
+// diskIOCollector reports throughput, IOPS, latency and utilisation for each whole disk.
+// Partitions and loop/ram devices are skipped so nothing is counted twice.
+type diskIOCollector struct {
+	prev     map[string]disk.IOCountersStat
+	prevTime time.Time
+}
+
+func (c *diskIOCollector) Name() string { return "diskio" }
+
+func (c *diskIOCollector) Collect(m *Metrics) error {
+	diskIO, err := disk.IOCounters()
+	if err != nil {
+		return fmt.Errorf("disk io counters: %w", err)
+	}
+
+	now := time.Now()
+	var elapsed float64
+	if !c.prevTime.IsZero() {
+		elapsed = now.Sub(c.prevTime).Seconds()
+	}
+
+	current := make(map[string]disk.IOCountersStat, len(diskIO))
+	devices := make([]DiskDeviceStats, 0, len(diskIO))
+	var readMBps, writeMBps, readIOPS, writeIOPS float64
+	for name, io := range diskIO {
+		kind := classifyBlockDevice(name)
+		if kind.partition || kind.virtual {
+			continue
+		}
+		current[name] = io
+
+		stats := DiskDeviceStats{Name: name, Stacked: kind.stacked}
+		if prev, ok := c.prev[name]; ok && elapsed > 0 {
+			stats.ReadMBps = rate(prev.ReadBytes, io.ReadBytes, elapsed) / 1024 / 1024
+			stats.WriteMBps = rate(prev.WriteBytes, io.WriteBytes, elapsed) / 1024 / 1024
+			stats.ReadIOPS = rate(prev.ReadCount, io.ReadCount, elapsed)
+			stats.WriteIOPS = rate(prev.WriteCount, io.WriteCount, elapsed)
+
+			// The kernel's time counters are in milliseconds
+			if ops := delta(prev.ReadCount, io.ReadCount) + delta(prev.WriteCount, io.WriteCount); ops > 0 {
+				stats.AwaitMs = (delta(prev.ReadTime, io.ReadTime) + delta(prev.WriteTime, io.WriteTime)) / ops
+			}
+			stats.QueueDepth = rate(prev.WeightedIO, io.WeightedIO, elapsed) / 1000
+			stats.UtilPercent = rate(prev.IoTime, io.IoTime, elapsed) / 1000 * 100
+			if stats.UtilPercent > 100 {
+				stats.UtilPercent = 100
+			}
+		}
+		devices = append(devices, stats)
+
+		if !stats.Stacked {
+			readMBps += stats.ReadMBps
+			writeMBps += stats.WriteMBps
+			readIOPS += stats.ReadIOPS
+			writeIOPS += stats.WriteIOPS
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Name < devices[j].Name })
+	c.prev, c.prevTime = current, now
+
+	m.DiskDevices = devices
+	m.DiskReadMBps, m.DiskWriteMBps = readMBps, writeMBps
+	m.DiskReadIOPS, m.DiskWriteIOPS = readIOPS, writeIOPS
+	if len(diskIO) == 0 {
+		return fmt.Errorf("%w: no block devices reported", ErrUnavailable)
+	}
+	return nil
+}
+
+// blockDeviceKind says how a block device relates to the others
+type blockDeviceKind struct {
+	partition bool // part of a whole disk that is also listed
+	stacked   bool // device-mapper or md device built on other disks
+	virtual   bool // loop or ram device with no disk behind it
+}
@@ -0,0 +1,80 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// classifyBlockDevice uses sysfs to tell whole disks from partitions and stacked devices
+func classifyBlockDevice(name string) blockDeviceKind {
+	var kind blockDeviceKind
+	if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+		kind.virtual = true
+		return kind
+	}
+	if _, err := os.Stat(filepath.Join("/sys/class/block", name, "partition")); err == nil {
+		kind.partition = true
+	}
+	if slaves, err := os.ReadDir(filepath.Join("/sys/class/block", name, "slaves")); err == nil && len(slaves) > 0 {
+		kind.stacked = true
+	}
+	return kind
+}
+
+// readSubvolumes maps each btrfs mount point to the subvolume mounted there, from the "subvol="
+// super option in /proc/1/mountinfo, the mount table gopsutil lists partitions from, whose
+// lines look like
+// "37 29 0:33 /@home /home rw,relatime shared:2 - btrfs /dev/sda2 rw,space_cache,subvol=/@home"
+func readSubvolumes() map[string]string {
+	subvolumes := make(map[string]string)
+	f, err := os.Open("/proc/1/mountinfo")
+	if err != nil {
+		return subvolumes
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		// The optional fields end at "-", after which come the type, source and super options
+		for i := 6; i+3 < len(fields); i++ {
+			if fields[i] != "-" {
+				continue
+			}
+			for _, opt := range strings.Split(fields[i+3], ",") {
+				if subvol, ok := strings.CutPrefix(opt, "subvol="); ok {
+					subvolumes[unescapeMountPath(fields[4])] = subvol
+				}
+			}
+			break
+		}
+	}
+	return subvolumes
+}
+
+// unescapeMountPath undoes mountinfo's octal escapes, such as "\040" for a space
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package metrics
+
+// classifyBlockDevice has no sysfs to consult, so every reported device is treated as a whole disk
+func classifyBlockDevice(name string) blockDeviceKind {
+	return blockDeviceKind{}
+}
+
+// readSubvolumes finds no btrfs subvolumes outside Linux
+func readSubvolumes() map[string]string {
+	return nil
+}
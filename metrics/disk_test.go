@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+func TestSelectPartitions(t *testing.T) {
+	partitions := []disk.PartitionStat{
+		{Device: "/dev/sda2", Mountpoint: "/home", Fstype: "btrfs"},
+		{Device: "/dev/sda2", Mountpoint: "/", Fstype: "btrfs"},
+		{Device: "/dev/sda2", Mountpoint: "/var/lib/docker/btrfs", Fstype: "btrfs"},
+		{Device: "/dev/sda1", Mountpoint: "/boot", Fstype: "vfat"},
+		{Device: "/dev/sdb1", Mountpoint: "/data", Fstype: "ext4"},
+		{Device: "/dev/sdb1", Mountpoint: "/srv/data", Fstype: "ext4"},
+		{Device: "/dev/sdb1", Mountpoint: "/data", Fstype: "ext4"},
+		{Device: "tmpfs", Mountpoint: "/tmp", Fstype: "tmpfs"},
+		{Device: "/dev/loop3", Mountpoint: "/snap/core/1", Fstype: "squashfs"},
+	}
+	subvolumes := map[string]string{
+		"/":                     "/@",
+		"/home":                 "/@home",
+		"/var/lib/docker/btrfs": "/@",
+	}
+
+	files := map[string]bool{"/etc/hosts": true, "/etc/resolv.conf": true}
+	isFile := func(path string) bool { return files[path] }
+
+	tests := []struct {
+		name             string
+		partitions       []disk.PartitionStat
+		subvolumes       map[string]string
+		include, exclude []string
+		want             []string
+	}{
+		{
+			name:       "subvolumes kept, bind mounts and pseudo filesystems dropped",
+			partitions: partitions,
+			subvolumes: subvolumes,
+			want:       []string{"/", "/home", "/boot", "/data"},
+		},
+		{
+			name:       "without subvolume information a shared device is listed once",
+			partitions: partitions,
+			want:       []string{"/", "/boot", "/data"},
+		},
+		{
+			name:       "patterns",
+			partitions: partitions,
+			subvolumes: subvolumes,
+			exclude:    []string{"/boot"},
+			want:       []string{"/", "/home", "/data"},
+		},
+		{
+			name: "overlay root in a container",
+			partitions: []disk.PartitionStat{
+				{Device: "overlay", Mountpoint: "/", Fstype: "overlay"},
+				{Device: "/dev/vda", Mountpoint: "/etc/hosts", Fstype: "ext4"},
+				{Device: "/dev/vda", Mountpoint: "/etc/resolv.conf", Fstype: "ext4"},
+				{Device: "/dev/vda", Mountpoint: "/data", Fstype: "ext4"},
+				{Device: "shm", Mountpoint: "/dev/shm", Fstype: "tmpfs"},
+			},
+			want: []string{"/", "/data"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range selectPartitions(tt.partitions, tt.subvolumes, isFile, tt.include, tt.exclude) {
+				got = append(got, p.Mountpoint)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectPartitions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ProcessFilter selects processes by an expression such as `java && cpu>20 || user=postgres`.
+//
+// Terms are joined with && and ||, && binding tighter, and a leading ! negates a term.
+// A term is one of:
+//
+//	java               regular expression matched against the name or the command line
+//	1234,5678          list of PIDs
+//	name~^node$        name (or cmd, user, cgroup, state) matching a regular expression; !~ negates
+//	user=alice,bob     name (or cmd, user, cgroup, state) equal to one of a list; != negates
+//	cpu>20             cpu, rss, vms, threads, nice, pid or ppid compared with > >= < <= = !=
+//	rss>1G             memory sizes take K, M, G or T suffixes (powers of 1024)
+//
+// A term or value can be quoted with ' or ", as in cmd~"a||b" or name='my app', to keep
+// && and || in it from being read as joins.
+type ProcessFilter struct {
+	expr  string
+	terms [][]processPredicate // any of these groups, where every predicate in a group must hold
+}
+
+type processPredicate func(p ProcessInfo) bool
+
+var filterTermPattern = regexp.MustCompile(`^([A-Za-z]+)\s*(!=|!~|>=|<=|==|=|~|>|<)\s*(.*)$`)
+
+var pidListPattern = regexp.MustCompile(`^\d+(\s*,\s*\d+)*$`)
+
+// processTextFields are the fields that compare as strings
+var processTextFields = map[string]func(p ProcessInfo) string{
+	"name":    func(p ProcessInfo) string { return p.Name },
+	"cmd":     func(p ProcessInfo) string { return p.Command },
+	"command": func(p ProcessInfo) string { return p.Command },
+	"user":    func(p ProcessInfo) string { return p.User },
+	"cgroup":  func(p ProcessInfo) string { return p.Cgroup },
+	"state":   func(p ProcessInfo) string { return p.State },
+}
+
+// processNumberFields are the fields that compare as numbers
+var processNumberFields = map[string]func(p ProcessInfo) float64{
+	"cpu":     func(p ProcessInfo) float64 { return p.CPUPercent },
+	"rss":     func(p ProcessInfo) float64 { return float64(p.RSS) },
+	"mem":     func(p ProcessInfo) float64 { return float64(p.RSS) },
+	"vms":     func(p ProcessInfo) float64 { return float64(p.VMS) },
+	"virt":    func(p ProcessInfo) float64 { return float64(p.VMS) },
+	"threads": func(p ProcessInfo) float64 { return float64(p.Threads) },
+	"nice":    func(p ProcessInfo) float64 { return float64(p.Nice) },
+	"pid":     func(p ProcessInfo) float64 { return float64(p.PID) },
+	"ppid":    func(p ProcessInfo) float64 { return float64(p.PPID) },
+}
+
+// ParseProcessFilter compiles a filter expression. An empty expression gives a nil filter,
+// which matches every process.
+func ParseProcessFilter(expr string) (*ProcessFilter, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, nil
+	}
+	groups, err := splitFilterExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+	f := &ProcessFilter{expr: expr}
+	for _, group := range groups {
+		var predicates []processPredicate
+		for _, term := range group {
+			predicate, err := parseFilterTerm(term)
+			if err != nil {
+				return nil, err
+			}
+			predicates = append(predicates, predicate)
+		}
+		f.terms = append(f.terms, predicates)
+	}
+	return f, nil
+}
+
+// splitFilterExpr breaks an expression into its ||-joined groups of &&-joined terms. Joins
+// inside quotes are part of the term; a quote only opens at the start of a term or value, so
+// an apostrophe in the middle of a word is taken literally.
+func splitFilterExpr(expr string) ([][]string, error) {
+	var groups [][]string
+	var group []string
+	var term strings.Builder
+	endTerm := func() {
+		group = append(group, strings.TrimSpace(term.String()))
+		term.Reset()
+	}
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case (c == '"' || c == '\'') && opensQuote(term.String()):
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in %q", expr[i:])
+			}
+			term.WriteString(expr[i : i+end+2])
+			i += end + 1
+		case strings.HasPrefix(expr[i:], "&&"):
+			endTerm()
+			i++
+		case strings.HasPrefix(expr[i:], "||"):
+			endTerm()
+			groups = append(groups, group)
+			group = nil
+			i++
+		default:
+			term.WriteByte(c)
+		}
+	}
+	endTerm()
+	return append(groups, group), nil
+}
+
+// opensQuote reports whether a quote following the text so far starts a quoted term or value
+func opensQuote(before string) bool {
+	before = strings.TrimSpace(before)
+	return before == "" || strings.ContainsRune("!=~<>", rune(before[len(before)-1]))
+}
+
+// unquote strips the quotes from a quoted term or value
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] && strings.Count(s, s[:1]) == 2 {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func parseFilterTerm(term string) (processPredicate, error) {
+	if term == "" {
+		return nil, fmt.Errorf("empty term")
+	}
+	if strings.HasPrefix(term, "!") && !strings.HasPrefix(term, "!=") && !strings.HasPrefix(term, "!~") {
+		inner, err := parseFilterTerm(strings.TrimSpace(term[1:]))
+		if err != nil {
+			return nil, err
+		}
+		return func(p ProcessInfo) bool { return !inner(p) }, nil
+	}
+
+	if pidListPattern.MatchString(term) {
+		pids := parsePIDList(term)
+		return func(p ProcessInfo) bool { return pids[p.PID] }, nil
+	}
+
+	match := filterTermPattern.FindStringSubmatch(term)
+	if match == nil {
+		return bareRegexPredicate(unquote(term))
+	}
+	field, op, value := strings.ToLower(match[1]), match[2], strings.TrimSpace(match[3])
+	_, isText := processTextFields[field]
+	_, isNumber := processNumberFields[field]
+	if (isText || isNumber) && value == "" {
+		return nil, fmt.Errorf("missing value after %s%s", match[1], op)
+	}
+	value = unquote(value)
+	if text, ok := processTextFields[field]; ok {
+		return textPredicate(field, text, op, value)
+	}
+	if number, ok := processNumberFields[field]; ok {
+		return numberPredicate(field, number, op, value)
+	}
+	// Not a known field, so treat the whole term as a pattern, e.g. "a=b" in a command line
+	return bareRegexPredicate(term)
+}
+
+func bareRegexPredicate(term string) (processPredicate, error) {
+	re, err := regexp.Compile(term)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", term, err)
+	}
+	return func(p ProcessInfo) bool {
+		return re.MatchString(p.Name) || re.MatchString(p.Command)
+	}, nil
+}
+
+func textPredicate(field string, text func(ProcessInfo) string, op, value string) (processPredicate, error) {
+	switch op {
+	case "~", "!~":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for %s: %w", field, err)
+		}
+		negate := op == "!~"
+		return func(p ProcessInfo) bool { return re.MatchString(text(p)) != negate }, nil
+	case "=", "==", "!=":
+		wanted := make(map[string]bool)
+		for _, v := range strings.Split(value, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				return nil, fmt.Errorf("empty value in %s list %q", field, value)
+			}
+			wanted[v] = true
+		}
+		negate := op == "!="
+		return func(p ProcessInfo) bool { return wanted[text(p)] != negate }, nil
+	}
+	return nil, fmt.Errorf("%s can't be compared with %s; use =, != or ~", field, op)
+}
+
+func numberPredicate(field string, number func(ProcessInfo) float64, op, value string) (processPredicate, error) {
+	if (field == "pid" || field == "ppid") && (op == "=" || op == "==" || op == "!=") && pidListPattern.MatchString(value) {
+		pids := parsePIDList(value)
+		negate := op == "!="
+		return func(p ProcessInfo) bool { return pids[int32(number(p))] != negate }, nil
+	}
+
+	limit, err := parseFilterNumber(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %w", field, err)
+	}
+	var compare func(a, b float64) bool
+	switch op {
+	case ">":
+		compare = func(a, b float64) bool { return a > b }
+	case ">=":
+		compare = func(a, b float64) bool { return a >= b }
+	case "<":
+		compare = func(a, b float64) bool { return a < b }
+	case "<=":
+		compare = func(a, b float64) bool { return a <= b }
+	case "=", "==":
+		compare = func(a, b float64) bool { return a == b }
+	case "!=":
+		compare = func(a, b float64) bool { return a != b }
+	default:
+		return nil, fmt.Errorf("%s can't be matched with %s; use a comparison such as >", field, op)
+	}
+	return func(p ProcessInfo) bool { return compare(number(p), limit) }, nil
+}
+
+func parsePIDList(value string) map[int32]bool {
+	pids := make(map[int32]bool)
+	for _, v := range strings.Split(value, ",") {
+		if pid, err := strconv.ParseInt(strings.TrimSpace(v), 10, 32); err == nil {
+			pids[int32(pid)] = true
+		}
+	}
+	return pids
+}
+
+// parseFilterNumber parses a plain number or a size like 512M, 1.5G or 2GiB
+func parseFilterNumber(value string) (float64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	if strings.HasSuffix(upper, "IB") {
+		upper = strings.TrimSuffix(upper, "IB")
+	} else {
+		upper = strings.TrimSuffix(upper, "B")
+	}
+	multiplier := 1.0
+	if upper != "" {
+		switch upper[len(upper)-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		case 'T':
+			multiplier = 1 << 40
+		}
+		if multiplier != 1 {
+			upper = upper[:len(upper)-1]
+		}
+	}
+	n, err := strconv.ParseFloat(upper, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	return n * multiplier, nil
+}
+
+// String returns the expression the filter was parsed from
+func (f *ProcessFilter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
+
+// Match reports whether a process passes the filter; a nil filter passes everything
+func (f *ProcessFilter) Match(p ProcessInfo) bool {
+	if f == nil {
+		return true
+	}
+	for _, group := range f.terms {
+		matched := true
+		for _, predicate := range group {
+			if !predicate(p) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the processes that pass the filter
+func (f *ProcessFilter) Filter(procs []ProcessInfo) []ProcessInfo {
+	if f == nil {
+		return procs
+	}
+	var matched []ProcessInfo
+	for _, p := range procs {
+		if f.Match(p) {
+			matched = append(matched, p)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,154 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+var filterTestProcesses = []ProcessInfo{
+	{PID: 1, PPID: 0, User: "root", Name: "systemd", Command: "/sbin/init", CPUPercent: 0.1, RSS: 12 << 20, Threads: 1, State: "sleep"},
+	{PID: 100, PPID: 1, User: "postgres", Name: "postgres", Command: "postgres -D /var/lib/pg", CPUPercent: 35, RSS: 2 << 30, Threads: 8, State: "running"},
+	{PID: 200, PPID: 1, User: "alice", Name: "java", Command: "java -Xmx1g -jar app.jar", CPUPercent: 25, RSS: 1536 << 20, Threads: 40, State: "running"},
+	{PID: 300, PPID: 200, User: "alice", Name: "node", Command: "sh -c node server.js --mode=a && echo done", CPUPercent: 5, RSS: 200 << 20, Threads: 11, State: "sleep"},
+	{PID: 400, PPID: 1, User: "bob", Name: "my app", Command: "my app", CPUPercent: 0, RSS: 1 << 30, Threads: 2, State: "zombie"},
+}
+
+func TestParseProcessFilter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []int32
+	}{
+		{"", []int32{1, 100, 200, 300, 400}},
+		{"java", []int32{200}},
+		{"!java", []int32{1, 100, 300, 400}},
+		{"! user=alice", []int32{1, 100, 400}},
+
+		// && binds tighter than ||
+		{"java || node && user=bob", []int32{200}},
+		{"node && user=alice || postgres", []int32{100, 300}},
+		{"user=alice && cpu>10 || user=bob", []int32{200, 400}},
+
+		// Text fields
+		{"user=alice,bob", []int32{200, 300, 400}},
+		{"user != alice, bob", []int32{1, 100}},
+		{"name~^(java|node)$", []int32{200, 300}},
+		{"name!~^(java|node)$", []int32{1, 100, 400}},
+		{"state=running", []int32{100, 200}},
+		{"NAME=java", []int32{200}},
+
+		// Numbers and sizes
+		{"cpu>20", []int32{100, 200}},
+		{"cpu>=25", []int32{100, 200}},
+		{"threads<2", []int32{1}},
+		{"rss>1G", []int32{100, 200}},
+		{"rss>=1G", []int32{100, 200, 400}},
+		{"rss>1.5GiB", []int32{100}},
+		{"rss>=1.5gib", []int32{100, 200}},
+		{"rss<200M", []int32{1}},
+		{"rss<=204800K", []int32{1, 300}},
+		{"rss=1073741824B", []int32{400}},
+
+		// PID lists
+		{"100,300", []int32{100, 300}},
+		{"100, 300", []int32{100, 300}},
+		{"pid=1,400", []int32{1, 400}},
+		{"pid!=1,400", []int32{100, 200, 300}},
+		{"ppid=200", []int32{300}},
+		{"pid>=300", []int32{300, 400}},
+
+		// Quoting keeps && and || inside a term
+		{`cmd~"js .*&& echo"`, []int32{300}},
+		{`"&& echo" && user=alice`, []int32{300}},
+		{`!'&& echo' && user=alice`, []int32{200}},
+		{`name='my app'`, []int32{400}},
+		{`cmd~it's`, nil},
+
+		// An unknown field makes the whole term a pattern
+		{"mode=a", []int32{300}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseProcessFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseProcessFilter(%q): %v", tt.expr, err)
+			}
+			var got []int32
+			for _, p := range f.Filter(filterTestProcesses) {
+				got = append(got, p.PID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseProcessFilter(%q) matched %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitFilterExpr(t *testing.T) {
+	tests := []struct {
+		expr string
+		want [][]string
+	}{
+		{"java", [][]string{{"java"}}},
+		{"a && b || c", [][]string{{"a", "b"}, {"c"}}},
+		{"a||b&&c", [][]string{{"a"}, {"b", "c"}}},
+		{`cmd~"a||b" && user=x`, [][]string{{`cmd~"a||b"`, "user=x"}}},
+		{`cmd~'a && b' || !"c||d"`, [][]string{{`cmd~'a && b'`}, {`!"c||d"`}}},
+		{`cmd~it's || x`, [][]string{{`cmd~it's`}, {"x"}}},
+		{"a &&", [][]string{{"a", ""}}},
+	}
+	for _, tt := range tests {
+		got, err := splitFilterExpr(tt.expr)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitFilterExpr(%q) = %q, %v; want %q", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseProcessFilterErrors(t *testing.T) {
+	for _, expr := range []string{
+		"java &&",
+		"|| java",
+		"java && && node",
+		"!",
+		"user=",
+		"user=alice,",
+		"user=alice,,bob",
+		"name~",
+		"cpu>",
+		"pid=",
+		"cpu>fast",
+		"rss>1.5GI",
+		"cpu~20",
+		"user>alice",
+		"name~(",
+		"(",
+		`cmd~"a||b`,
+	} {
+		if f, err := ParseProcessFilter(expr); err == nil {
+			t.Errorf("ParseProcessFilter(%q) = %v, want an error", expr, f)
+		}
+	}
+}
+
+func TestParseFilterNumber(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{"20", 20},
+		{"0.5", 0.5},
+		{"512K", 512 << 10},
+		{"512kb", 512 << 10},
+		{"1M", 1 << 20},
+		{"1G", 1 << 30},
+		{"1.5GiB", 1.5 * (1 << 30)},
+		{"2T", 2 << 40},
+		{"100B", 100},
+	}
+	for _, tt := range tests {
+		got, err := parseFilterNumber(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("parseFilterNumber(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// gpuCollector detects GPUs from temperature sensors, nvidia-smi and sysfs
+type gpuCollector struct{}
+
+func (c *gpuCollector) Name() string { return "gpu" }
+
+func (c *gpuCollector) Collect(m *Metrics) error {
+	var gpus []GPUInfo
+
+	// Get temperature sensors for GPU detection
+	temps, sensorErr := host.SensorsTemperatures()
+
+	// First, detect GPUs from temperature sensors
+	for _, t := range temps {
+		sensorKey := strings.ToLower(t.SensorKey)
+		if strings.Contains(sensorKey, "gpu") ||
+			strings.Contains(sensorKey, "amdgpu") ||
+			strings.Contains(sensorKey, "nvidia") ||
+			strings.Contains(sensorKey, "edge") { // AMD GPU edge temperature
+
+			// Extract GPU name from sensor key
+			gpuName := "GPU"
+			if strings.Contains(sensorKey, "amdgpu") {
+				gpuName = "AMD GPU"
+			} else if strings.Contains(sensorKey, "nvidia") {
+				gpuName = "NVIDIA GPU"
+			} else if strings.Contains(sensorKey, "edge") {
+				gpuName = "AMD GPU"
+			}
+
+			gpus = append(gpus, GPUInfo{
+				Name:        gpuName,
+				MemoryUsed:  0, // Not available without GPU-specific libraries
+				MemoryTotal: 0, // Not available without GPU-specific libraries
+				Temperature: t.Temperature,
+				Utilization: 0, // Not available without GPU-specific libraries
+			})
+		}
+	}
+
+	// Check for NVIDIA GPU via nvidia-smi if not already detected
+	nvidiaDetected := false
+	for _, gpu := range gpus {
+		if strings.Contains(strings.ToLower(gpu.Name), "nvidia") {
+			nvidiaDetected = true
+			break
+		}
+	}
+
+	if !nvidiaDetected {
+		// Try to get NVIDIA GPU temperature and utilization via nvidia-smi
+		tempCmd := exec.Command("nvidia-smi", "--query-gpu=temperature.gpu", "--format=csv,noheader,nounits")
+		utilCmd := exec.Command("nvidia-smi", "--query-gpu=utilization.gpu", "--format=csv,noheader,nounits")
+
+		var temp, util float64
+		if output, err := tempCmd.Output(); err == nil {
+			if tempStr := strings.TrimSpace(string(output)); tempStr != "" {
+				temp, _ = strconv.ParseFloat(tempStr, 64)
+			}
+		}
+		if output, err := utilCmd.Output(); err == nil {
+			if utilStr := strings.TrimSpace(string(output)); utilStr != "" {
+				util, _ = strconv.ParseFloat(strings.TrimRight(utilStr, " %"), 64)
+			}
+		}
+
+		if temp > 0 || util > 0 {
+			gpus = append(gpus, GPUInfo{
+				Name:        "NVIDIA GPU",
+				Temperature: temp,
+				Utilization: util,
+			})
+		}
+	}
+
+	// AMD GPU Utilization
+	for i, gpu := range gpus {
+		if strings.Contains(strings.ToLower(gpu.Name), "amd") {
+			// Try to read utilization from sysfs
+			if utilData, err := exec.Command("cat", "/sys/class/drm/card0/device/gpu_busy_percent").Output(); err == nil {
+				if utilStr := strings.TrimSpace(string(utilData)); utilStr != "" {
+					gpus[i].Utilization, _ = strconv.ParseFloat(utilStr, 64)
+				}
+			}
+		}
+	}
+
+	m.GPUs = gpus
+	if len(gpus) == 0 {
+		if len(temps) == 0 && sensorErr != nil {
+			return fmt.Errorf("temperature sensors: %w", sensorErr)
+		}
+		return fmt.Errorf("%w: no GPU detected", ErrUnavailable)
+	}
+	return nil
+}
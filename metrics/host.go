@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/distatus/battery"
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// temperatureCollector reads every hardware sensor where it can (Linux), picking CPU Temp out
+// of them, and falls back to the platform-specific GetCPUTemperature when that finds none
+type temperatureCollector struct {
+	cpuSensor string // path.Match pattern for the CPU Temp sensor, e.g. "k10temp/Tctl"; empty picks one
+}
+
+// NewTemperatureCollector returns the temperature collector, with CPU Temp taken from the
+// first sensor whose "chip/label" name matches cpuSensor, or chosen automatically if it is empty
+func NewTemperatureCollector(cpuSensor string) Collector {
+	return &temperatureCollector{cpuSensor: cpuSensor}
+}
+
+func (c *temperatureCollector) Name() string { return "temperature" }
+
+func (c *temperatureCollector) Collect(m *Metrics) error {
+	chips, err := readSensors()
+	m.Sensors, m.CoreTemps = chips, coreTemperatures(chips)
+	m.CPUTemp, m.CPUTempSensor, _ = selectCPUTemp(chips, c.cpuSensor)
+	// A configured sensor that is missing is reported rather than replaced by a guess
+	if m.CPUTemp == 0 && (err != nil || c.cpuSensor == "") {
+		m.CPUTemp = GetCPUTemperature()
+	}
+	if m.CPUTemp == 0 {
+		if c.cpuSensor != "" && err == nil {
+			return fmt.Errorf("%w: no temperature sensor matches %q", ErrUnavailable, c.cpuSensor)
+		}
+		return fmt.Errorf("%w: no CPU temperature sensor found", ErrUnavailable)
+	}
+	return nil
+}
+
+// batteryCollector reports charge of the first battery; charge moves slowly so it runs every few seconds
+type batteryCollector struct{}
+
+func (c *batteryCollector) Name() string { return "battery" }
+
+func (c *batteryCollector) Interval() time.Duration { return 5 * time.Second }
+
+func (c *batteryCollector) Collect(m *Metrics) error {
+	m.BatteryPercent = 0.0
+	m.BatteryState = "N/A"
+	batStats, err := battery.GetAll()
+	if len(batStats) > 0 && batStats[0] != nil && batStats[0].Full > 0 {
+		bat := batStats[0]
+		m.BatteryPercent = (bat.Current / bat.Full) * 100
+		m.BatteryState = bat.State.String()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("battery: %w", err)
+	}
+	return fmt.Errorf("%w: no battery found", ErrUnavailable)
+}
+
+// uptimeCollector reports time since boot
+type uptimeCollector struct{}
+
+func (c *uptimeCollector) Name() string { return "uptime" }
+
+func (c *uptimeCollector) Collect(m *Metrics) error {
+	uptime, err := host.Uptime()
+	if err != nil {
+		return fmt.Errorf("host uptime: %w", err)
+	}
+	m.UptimeDays = int(uptime / 86400)
+	m.UptimeHours = int((uptime % 86400) / 3600)
+	m.UptimeMinutes = int((uptime % 3600) / 60)
+	return nil
+}
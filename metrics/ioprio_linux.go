@@ -0,0 +1,21 @@
+//go:build linux
+
+package metrics
+
+import "syscall"
+
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+)
+
+// SetIOPriority sets the I/O scheduling class and level (0 highest to 7 lowest) of a process,
+// like ionice. The level is ignored for the idle class.
+func SetIOPriority(pid int32, class IOPriorityClass, level int) error {
+	prio := int(class)<<ioprioClassShift | level
+	_, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(pid), uintptr(prio))
+	if errno != 0 {
+		return controlError("set I/O priority of", pid, errno)
+	}
+	return nil
+}
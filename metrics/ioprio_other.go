@@ -0,0 +1,10 @@
+//go:build !linux
+
+package metrics
+
+import "fmt"
+
+// SetIOPriority is only supported on Linux
+func SetIOPriority(pid int32, class IOPriorityClass, level int) error {
+	return fmt.Errorf("%w: I/O priority is only supported on Linux", ErrUnavailable)
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"time"
+)
+
+// schedStats is a snapshot of load averages and scheduler counters
+type schedStats struct {
+	load1, load5, load15       float64
+	procsRunning, procsBlocked int
+	ctxt, intr                 uint64
+	hasCounters                bool // ctxt and intr are only known where the kernel exposes them
+}
+
+// loadCollector reports load averages, the run queue, and context switch and interrupt rates
+type loadCollector struct {
+	prev     schedStats
+	prevTime time.Time
+}
+
+func (c *loadCollector) Name() string { return "load" }
+
+func (c *loadCollector) Collect(m *Metrics) error {
+	stats, err := readSchedStats()
+	if err != nil {
+		return err
+	}
+
+	m.Load1, m.Load5, m.Load15 = stats.load1, stats.load5, stats.load15
+	m.ProcsRunning, m.ProcsBlocked = stats.procsRunning, stats.procsBlocked
+
+	now := time.Now()
+	if stats.hasCounters && !c.prevTime.IsZero() {
+		elapsed := now.Sub(c.prevTime).Seconds()
+		m.ContextSwitchesPerSec = rate(c.prev.ctxt, stats.ctxt, elapsed)
+		m.InterruptsPerSec = rate(c.prev.intr, stats.intr, elapsed)
+	}
+	c.prev, c.prevTime = stats, now
+	return nil
+}
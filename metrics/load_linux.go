@@ -0,0 +1,65 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readSchedStats reads load averages from /proc/loadavg and run queue and counters from /proc/stat
+func readSchedStats() (schedStats, error) {
+	var stats schedStats
+
+	loadavg, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return stats, fmt.Errorf("read loadavg: %w", err)
+	}
+	fields := strings.Fields(string(loadavg))
+	if len(fields) < 3 {
+		return stats, fmt.Errorf("parse loadavg: unexpected content %q", string(loadavg))
+	}
+	loads := []*float64{&stats.load1, &stats.load5, &stats.load15}
+	for i, load := range loads {
+		if *load, err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return stats, fmt.Errorf("parse loadavg: %w", err)
+		}
+	}
+
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return stats, fmt.Errorf("read stat: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // the intr line lists every IRQ and can be long
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "ctxt":
+			stats.ctxt = value
+		case "intr":
+			stats.intr = value
+		case "procs_running":
+			stats.procsRunning = int(value)
+		case "procs_blocked":
+			stats.procsBlocked = int(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, fmt.Errorf("read stat: %w", err)
+	}
+	stats.hasCounters = true
+	return stats, nil
+}
@@ -0,0 +1,26 @@
+//go:build !linux
+
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/load"
+)
+
+// readSchedStats uses gopsutil's generic load functions; context switch and interrupt
+// counters aren't available outside Linux
+func readSchedStats() (schedStats, error) {
+	var stats schedStats
+
+	avg, err := load.Avg()
+	if err != nil {
+		return stats, fmt.Errorf("load average: %w", err)
+	}
+	stats.load1, stats.load5, stats.load15 = avg.Load1, avg.Load5, avg.Load15
+
+	if misc, err := load.Misc(); err == nil {
+		stats.procsRunning, stats.procsBlocked = misc.ProcsRunning, misc.ProcsBlocked
+	}
+	return stats, nil
+}
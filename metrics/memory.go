@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// MemoryBreakdown explains where RAM went, in bytes. Most fields are only filled in on Linux.
+type MemoryBreakdown struct {
+	Used              uint64
+	Buffers           uint64
+	Cached            uint64 // page cache, excluding shared memory and reclaimable slab
+	Shared            uint64 // shmem, including tmpfs
+	SlabReclaimable   uint64
+	SlabUnreclaimable uint64
+	Dirty             uint64
+	Writeback         uint64
+	Anon              uint64
+	File              uint64
+	HugePagesTotal    uint64
+	HugePagesFree     uint64
+	HugePageSize      uint64
+	AnonHugePages     uint64
+	CommittedAS       uint64
+	CommitLimit       uint64
+}
+
+// memoryCollector reports RAM usage and its breakdown
+type memoryCollector struct{}
+
+func (c *memoryCollector) Name() string { return "memory" }
+
+func (c *memoryCollector) Collect(m *Metrics) error {
+	memStats, err := mem.VirtualMemory()
+	if err != nil {
+		return fmt.Errorf("virtual memory: %w", err)
+	}
+	m.MemoryUsage = memStats.UsedPercent
+	m.MemoryTotal = memStats.Total
+	m.MemoryAvailable = memStats.Available
+	m.MemoryCached = memStats.Cached
+
+	// gopsutil folds reclaimable slab into Cached, and the kernel counts shmem there too
+	pageCache := memStats.Cached
+	for _, part := range []uint64{memStats.Sreclaimable, memStats.Shared} {
+		if pageCache >= part {
+			pageCache -= part
+		}
+	}
+	breakdown := MemoryBreakdown{
+		Used:              memStats.Used,
+		Buffers:           memStats.Buffers,
+		Cached:            pageCache,
+		Shared:            memStats.Shared,
+		SlabReclaimable:   memStats.Sreclaimable,
+		SlabUnreclaimable: memStats.Sunreclaim,
+		Dirty:             memStats.Dirty,
+		Writeback:         memStats.WriteBack,
+		HugePagesTotal:    memStats.HugePagesTotal,
+		HugePagesFree:     memStats.HugePagesFree,
+		HugePageSize:      memStats.HugePageSize,
+		AnonHugePages:     memStats.AnonHugePages,
+		CommittedAS:       memStats.CommittedAS,
+		CommitLimit:       memStats.CommitLimit,
+	}
+	anon, file, err := readAnonFileMemory()
+	if err != nil {
+		m.MemoryBreakdown = breakdown
+		return err
+	}
+	breakdown.Anon, breakdown.File = anon, file
+	m.MemoryBreakdown = breakdown
+	return nil
+}
+
+// SwapDeviceInfo is one swap partition or file
+type SwapDeviceInfo struct {
+	Name  string
+	Used  uint64
+	Total uint64
+}
+
+// swapCollector reports swap usage per device and how fast pages move in and out of it
+type swapCollector struct {
+	prevIn, prevOut, prevMajFault uint64
+	prevTime                      time.Time
+}
+
+func (c *swapCollector) Name() string { return "swap" }
+
+func (c *swapCollector) Collect(m *Metrics) error {
+	swapStats, err := mem.SwapMemory()
+	if err != nil {
+		return fmt.Errorf("swap memory: %w", err)
+	}
+	m.SwapUsage = swapStats.UsedPercent
+	m.SwapTotal = swapStats.Total
+	m.SwapUsed = swapStats.Used
+
+	// gopsutil reports swap-in/out and major faults in bytes, assuming 4 KiB pages
+	majFaults := swapStats.PgMajFault / 4096
+	now := time.Now()
+	if !c.prevTime.IsZero() {
+		elapsed := now.Sub(c.prevTime).Seconds()
+		m.SwapInMBps = rate(c.prevIn, swapStats.Sin, elapsed) / (1 << 20)
+		m.SwapOutMBps = rate(c.prevOut, swapStats.Sout, elapsed) / (1 << 20)
+		m.MajorFaultsPerSec = rate(c.prevMajFault, majFaults, elapsed)
+	}
+	c.prevIn, c.prevOut, c.prevMajFault, c.prevTime = swapStats.Sin, swapStats.Sout, majFaults, now
+
+	devices, err := mem.SwapDevices()
+	if err != nil {
+		return fmt.Errorf("swap devices: %w", err)
+	}
+	swapDevices := make([]SwapDeviceInfo, 0, len(devices))
+	for _, d := range devices {
+		swapDevices = append(swapDevices, SwapDeviceInfo{
+			Name:  d.Name,
+			Used:  d.UsedBytes,
+			Total: d.UsedBytes + d.FreeBytes,
+		})
+	}
+	m.SwapDevices = swapDevices
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build linux
+
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// readAnonFileMemory splits LRU memory into anonymous and file-backed pages
+func readAnonFileMemory() (anon, file uint64, err error) {
+	ex, err := mem.VirtualMemoryEx()
+	if err != nil {
+		return 0, 0, fmt.Errorf("extended memory info: %w", err)
+	}
+	return ex.ActiveAnon + ex.InactiveAnon, ex.ActiveFile + ex.InactiveFile, nil
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import "time"
+
+// NetHealth are the kernel's protocol counters that point at network trouble, as rates, plus
+// the socket usage from /proc/net/sockstat (Linux only)
+type NetHealth struct {
+	// From /proc/net/snmp and /proc/net/netstat
+	TCPRetransPerSec      float64 // segments retransmitted
+	TCPRetransPercent     float64 // of segments sent
+	TCPOutRstsPerSec      float64 // RSTs sent
+	TCPEstabResetsPerSec  float64 // established connections reset
+	ListenOverflowsPerSec float64 // connections dropped because an accept queue was full
+	ListenDropsPerSec     float64 // SYNs dropped at a listener, overflows included
+	UDPInErrorsPerSec     float64 // datagrams that couldn't be delivered, buffer errors included
+	UDPRcvbufErrorsPerSec float64 // datagrams dropped because a socket's receive buffer was full
+
+	// From /proc/net/sockstat; memory is in bytes
+	SocketsUsed uint64
+	TCPInUse    uint64
+	TCPOrphans  uint64
+	TCPTimeWait uint64
+	TCPMemory   uint64
+	UDPInUse    uint64
+	UDPMemory   uint64
+}
+
+// netCounters are the cumulative protocol counters the rates are worked out from
+type netCounters struct {
+	retrans, outSegs, outRsts, estabResets uint64
+	listenOverflows, listenDrops           uint64
+	udpInErrors, udpRcvbufErrors           uint64
+}
+
+// netHealthCollector turns the kernel's protocol counters into rates
+type netHealthCollector struct {
+	prev     netCounters
+	prevTime time.Time
+}
+
+func (c *netHealthCollector) Name() string { return "nethealth" }
+
+func (c *netHealthCollector) Collect(m *Metrics) error {
+	counters, health, err := readNetHealth()
+	if err != nil {
+		m.NetHealth = NetHealth{}
+		return err
+	}
+
+	now := time.Now()
+	if !c.prevTime.IsZero() {
+		elapsed := now.Sub(c.prevTime).Seconds()
+		health.TCPRetransPerSec = rate(c.prev.retrans, counters.retrans, elapsed)
+		if sent := delta(c.prev.outSegs, counters.outSegs); sent > 0 {
+			health.TCPRetransPercent = delta(c.prev.retrans, counters.retrans) / sent * 100
+		}
+		health.TCPOutRstsPerSec = rate(c.prev.outRsts, counters.outRsts, elapsed)
+		health.TCPEstabResetsPerSec = rate(c.prev.estabResets, counters.estabResets, elapsed)
+		health.ListenOverflowsPerSec = rate(c.prev.listenOverflows, counters.listenOverflows, elapsed)
+		health.ListenDropsPerSec = rate(c.prev.listenDrops, counters.listenDrops, elapsed)
+		health.UDPInErrorsPerSec = rate(c.prev.udpInErrors, counters.udpInErrors, elapsed)
+		health.UDPRcvbufErrorsPerSec = rate(c.prev.udpRcvbufErrors, counters.udpRcvbufErrors, elapsed)
+	}
+	c.prev, c.prevTime = counters, now
+
+	m.NetHealth = health
+	return nil
+}
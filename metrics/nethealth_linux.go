@@ -0,0 +1,112 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func readNetHealth() (netCounters, NetHealth, error) {
+	snmp, err := readProtocolCounters("/proc/net/snmp")
+	if err != nil {
+		return netCounters{}, NetHealth{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	// netstat holds the TcpExt counters; without it the overflow figures just stay at zero
+	netstat, _ := readProtocolCounters("/proc/net/netstat")
+
+	counters := netCounters{
+		retrans:         snmp["Tcp"]["RetransSegs"],
+		outSegs:         snmp["Tcp"]["OutSegs"],
+		outRsts:         snmp["Tcp"]["OutRsts"],
+		estabResets:     snmp["Tcp"]["EstabResets"],
+		listenOverflows: netstat["TcpExt"]["ListenOverflows"],
+		listenDrops:     netstat["TcpExt"]["ListenDrops"],
+		udpInErrors:     snmp["Udp"]["InErrors"],
+		udpRcvbufErrors: snmp["Udp"]["RcvbufErrors"],
+	}
+
+	var health NetHealth
+	sockstat, err := readSockstat("/proc/net/sockstat")
+	if err != nil {
+		return counters, health, fmt.Errorf("read sockstat: %w", err)
+	}
+	pageSize := uint64(os.Getpagesize())
+	health.SocketsUsed = sockstat["sockets"]["used"]
+	health.TCPInUse = sockstat["TCP"]["inuse"]
+	health.TCPOrphans = sockstat["TCP"]["orphan"]
+	health.TCPTimeWait = sockstat["TCP"]["tw"]
+	health.TCPMemory = sockstat["TCP"]["mem"] * pageSize
+	health.UDPInUse = sockstat["UDP"]["inuse"]
+	health.UDPMemory = sockstat["UDP"]["mem"] * pageSize
+	return counters, health, nil
+}
+
+// readProtocolCounters parses /proc/net/snmp and /proc/net/netstat, where each protocol has a
+// line of counter names followed by a line of values:
+//
+//	Tcp: RtoAlgorithm RtoMin RtoMax MaxConn ActiveOpens ...
+//	Tcp: 1 200 120000 -1 10 ...
+//
+// Signed values such as MaxConn are left out.
+func readProtocolCounters(path string) (map[string]map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	counters := make(map[string]map[string]uint64)
+	var names []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		protocol := strings.TrimSuffix(fields[0], ":")
+		if names == nil || names[0] != fields[0] {
+			names = fields
+			continue
+		}
+		values := make(map[string]uint64, len(fields)-1)
+		for i, field := range fields[1:] {
+			if i+1 >= len(names) {
+				break
+			}
+			if n, err := strconv.ParseUint(field, 10, 64); err == nil {
+				values[names[i+1]] = n
+			}
+		}
+		counters[protocol] = values
+		names = nil
+	}
+	return counters, scanner.Err()
+}
+
+// readSockstat parses lines like "TCP: inuse 4 orphan 0 tw 0 alloc 4 mem 0"
+func readSockstat(path string) (map[string]map[string]uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]map[string]uint64)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		values := make(map[string]uint64)
+		for i := 1; i+1 < len(fields); i += 2 {
+			if n, err := strconv.ParseUint(fields[i+1], 10, 64); err == nil {
+				values[fields[i]] = n
+			}
+		}
+		stats[strings.TrimSuffix(fields[0], ":")] = values
+	}
+	return stats, nil
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestReadProtocolCounters(t *testing.T) {
+	type counter struct {
+		protocol, name string
+		value          uint64
+		ok             bool
+	}
+	tests := []struct {
+		file string
+		want []counter
+	}{
+		{"snmp", []counter{
+			{"Tcp", "RetransSegs", 2871, true},
+			{"Tcp", "OutSegs", 4381920, true},
+			{"Tcp", "EstabResets", 977, true},
+			{"Tcp", "MaxConn", 0, false}, // signed, -1
+			{"Udp", "InErrors", 57, true},
+			{"Udp", "RcvbufErrors", 41, true},
+			{"UdpLite", "RcvbufErrors", 0, true},
+			{"Icmp", "OutMsgs", 318, true},
+		}},
+		// Older kernels have no UDP buffer errors; the other counters must not shift
+		{"snmp-old", []counter{
+			{"Tcp", "RetransSegs", 88, true},
+			{"Tcp", "OutRsts", 31, true},
+			{"Tcp", "InCsumErrors", 0, false},
+			{"Udp", "InErrors", 7, true},
+			{"Udp", "OutDatagrams", 1300, true},
+			{"Udp", "RcvbufErrors", 0, false},
+		}},
+		{"netstat", []counter{
+			{"TcpExt", "ListenOverflows", 25, true},
+			{"TcpExt", "ListenDrops", 31, true},
+			{"TcpExt", "TCPTimeouts", 950, true},
+			{"IpExt", "InOctets", 6201128812, true},
+		}},
+		{"netstat-old", []counter{
+			{"TcpExt", "ListenOverflows", 3, true},
+			{"TcpExt", "ListenDrops", 5, true},
+			{"TcpExt", "TCPTimeouts", 0, false},
+		}},
+	}
+	for _, tt := range tests {
+		counters, err := readProtocolCounters(filepath.Join("testdata", "nethealth", tt.file))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.file, err)
+		}
+		for _, c := range tt.want {
+			value, ok := counters[c.protocol][c.name]
+			if value != c.value || ok != c.ok {
+				t.Errorf("%s: %s %s = %d, %v; want %d, %v", tt.file, c.protocol, c.name, value, ok, c.value, c.ok)
+			}
+		}
+	}
+
+	if _, err := readProtocolCounters(filepath.Join("testdata", "nethealth", "missing")); err == nil {
+		t.Error("readProtocolCounters of a missing file succeeded")
+	}
+}
+
+func TestReadSockstat(t *testing.T) {
+	stats, err := readSockstat(filepath.Join("testdata", "nethealth", "sockstat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		protocol, name string
+		want           uint64
+	}{
+		{"sockets", "used", 412},
+		{"TCP", "inuse", 38},
+		{"TCP", "orphan", 2},
+		{"TCP", "tw", 19},
+		{"TCP", "mem", 27},
+		{"UDP", "inuse", 9},
+		{"UDP", "mem", 6},
+		{"RAW", "inuse", 1},
+	}
+	for _, tt := range tests {
+		if got := stats[tt.protocol][tt.name]; got != tt.want {
+			t.Errorf("%s %s = %d, want %d", tt.protocol, tt.name, got, tt.want)
+		}
+	}
+	if _, ok := stats["UDPLITE"]["mem"]; ok {
+		t.Error("UDPLITE has a mem figure the file doesn't give")
+	}
+}
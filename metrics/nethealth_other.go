@@ -0,0 +1,10 @@
+//go:build !linux
+
+package metrics
+
+import "fmt"
+
+// readNetHealth needs /proc/net, which only Linux has
+func readNetHealth() (netCounters, NetHealth, error) {
+	return netCounters{}, NetHealth{}, fmt.Errorf("%w: protocol counters are read from /proc/net, Linux only", ErrUnavailable)
+}
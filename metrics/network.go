@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// NetInterfaceStats holds the counters and rates of one network interface
+type NetInterfaceStats struct {
+	Name        string
+	BytesSent   uint64
+	BytesRecv   uint64
+	PacketsSent uint64
+	PacketsRecv uint64
+	ErrIn       uint64
+	ErrOut      uint64
+	DropIn      uint64
+	DropOut     uint64
+
+	SentMBps          float64
+	RecvMBps          float64
+	PacketsSentPerSec float64
+	PacketsRecvPerSec float64
+	ErrorsPerSec      float64
+	DropsPerSec       float64
+}
+
+// networkCollector reports per-interface traffic, plus totals over the interfaces it keeps
+type networkCollector struct {
+	include, exclude []string
+	prev             map[string]net.IOCountersStat
+	prevTime         time.Time
+}
+
+// NewNetworkCollector returns the network collector restricted to interfaces matching the
+// include patterns (all if empty) and none of the exclude patterns, e.g. "lo" or "veth*".
+// Patterns use path.Match syntax.
+func NewNetworkCollector(include, exclude []string) Collector {
+	return &networkCollector{include: include, exclude: exclude}
+}
+
+func (c *networkCollector) Name() string { return "network" }
+
+func (c *networkCollector) Collect(m *Metrics) error {
+	netIO, err := net.IOCounters(true)
+	if err != nil {
+		return fmt.Errorf("net io counters: %w", err)
+	}
+
+	now := time.Now()
+	var elapsed float64
+	if !c.prevTime.IsZero() {
+		elapsed = now.Sub(c.prevTime).Seconds()
+	}
+
+	current := make(map[string]net.IOCountersStat, len(netIO))
+	interfaces := make([]NetInterfaceStats, 0, len(netIO))
+	var total NetInterfaceStats
+	for _, io := range netIO {
+		if !matchPatterns(io.Name, c.include, c.exclude) {
+			continue
+		}
+		current[io.Name] = io
+
+		stats := NetInterfaceStats{
+			Name:        io.Name,
+			BytesSent:   io.BytesSent,
+			BytesRecv:   io.BytesRecv,
+			PacketsSent: io.PacketsSent,
+			PacketsRecv: io.PacketsRecv,
+			ErrIn:       io.Errin,
+			ErrOut:      io.Errout,
+			DropIn:      io.Dropin,
+			DropOut:     io.Dropout,
+		}
+		if prev, ok := c.prev[io.Name]; ok && elapsed > 0 {
+			stats.SentMBps = rate(prev.BytesSent, io.BytesSent, elapsed) / 1024 / 1024
+			stats.RecvMBps = rate(prev.BytesRecv, io.BytesRecv, elapsed) / 1024 / 1024
+			stats.PacketsSentPerSec = rate(prev.PacketsSent, io.PacketsSent, elapsed)
+			stats.PacketsRecvPerSec = rate(prev.PacketsRecv, io.PacketsRecv, elapsed)
+			stats.ErrorsPerSec = rate(prev.Errin+prev.Errout, io.Errin+io.Errout, elapsed)
+			stats.DropsPerSec = rate(prev.Dropin+prev.Dropout, io.Dropin+io.Dropout, elapsed)
+		}
+		interfaces = append(interfaces, stats)
+
+		total.SentMBps += stats.SentMBps
+		total.RecvMBps += stats.RecvMBps
+		total.PacketsSent += stats.PacketsSent
+		total.PacketsRecv += stats.PacketsRecv
+	}
+	c.prev, c.prevTime = current, now
+
+	m.NetInterfaces = interfaces
+	m.NetSentMBps = total.SentMBps
+	m.NetRecvMBps = total.RecvMBps
+	m.NetworkPacketsSent = total.PacketsSent
+	m.NetworkPacketsRecv = total.PacketsRecv
+	if len(netIO) == 0 {
+		return fmt.Errorf("%w: no network interfaces reported", ErrUnavailable)
+	}
+	return nil
+}
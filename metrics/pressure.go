@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// pressureResources are the files under /proc/pressure, in display order
+var pressureResources = []string{"cpu", "memory", "io"}
+
+// PressureLine is one "some" or "full" line of a PSI file
+type PressureLine struct {
+	Avg10  float64 // percent of time stalled, averaged over 10s
+	Avg60  float64
+	Avg300 float64
+	Total  time.Duration // cumulative stall time since boot
+
+	// StallPercent is the share of wall time stalled since the previous sample,
+	// from the change in Total
+	StallPercent float64
+}
+
+// PressureStats is the Pressure Stall Information for one resource
+type PressureStats struct {
+	Resource string
+	Some     PressureLine // at least one task stalled
+	Full     PressureLine // all non-idle tasks stalled at once
+	HasFull  bool
+}
+
+// pressureCollector reads Linux PSI from /proc/pressure
+type pressureCollector struct {
+	root     string
+	prev     map[string]PressureStats
+	prevTime time.Time
+}
+
+func (c *pressureCollector) Name() string { return "pressure" }
+
+func (c *pressureCollector) Collect(m *Metrics) error {
+	root := c.root
+	if root == "" {
+		root = "/proc/pressure"
+	}
+
+	now := time.Now()
+	var elapsed time.Duration
+	if !c.prevTime.IsZero() {
+		elapsed = now.Sub(c.prevTime)
+	}
+
+	var pressure []PressureStats
+	current := make(map[string]PressureStats)
+	for _, resource := range pressureResources {
+		stats, err := readPressureFile(filepath.Join(root, resource))
+		if err != nil {
+			m.Pressure = nil
+			if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.EOPNOTSUPP) {
+				return fmt.Errorf("%w: kernel has no PSI support", ErrUnavailable)
+			}
+			return fmt.Errorf("pressure %s: %w", resource, err)
+		}
+		stats.Resource = resource
+
+		if prev, ok := c.prev[resource]; ok && elapsed > 0 {
+			stats.Some.StallPercent = stallPercent(prev.Some.Total, stats.Some.Total, elapsed)
+			stats.Full.StallPercent = stallPercent(prev.Full.Total, stats.Full.Total, elapsed)
+		}
+		current[resource] = stats
+		pressure = append(pressure, stats)
+	}
+
+	m.Pressure = pressure
+	c.prev, c.prevTime = current, now
+	return nil
+}
+
+func stallPercent(prev, cur, elapsed time.Duration) float64 {
+	if cur < prev {
+		return 0
+	}
+	return float64(cur-prev) / float64(elapsed) * 100
+}
+
+// readPressureFile parses lines like "some avg10=0.12 avg60=0.05 avg300=0.01 total=12345"
+func readPressureFile(path string) (PressureStats, error) {
+	var stats PressureStats
+
+	f, err := os.Open(path)
+	if err != nil {
+		return stats, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var line PressureLine
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			switch key {
+			case "avg10":
+				line.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				line.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				line.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				var micros uint64
+				micros, err = strconv.ParseUint(value, 10, 64)
+				line.Total = time.Duration(micros) * time.Microsecond
+			}
+			if err != nil {
+				return stats, fmt.Errorf("parse %s: %w", key, err)
+			}
+		}
+
+		switch fields[0] {
+		case "some":
+			stats.Some = line
+		case "full":
+			stats.Full = line
+			stats.HasFull = true
+		}
+	}
+	return stats, scanner.Err()
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPressureCollectorFixture(t *testing.T) {
+	c := &pressureCollector{root: filepath.Join("testdata", "pressure")}
+	var m Metrics
+	if err := c.Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(m.Pressure) != len(pressureResources) {
+		t.Fatalf("got %d resources, want %d", len(m.Pressure), len(pressureResources))
+	}
+
+	cpu := m.Pressure[0]
+	if cpu.Resource != "cpu" {
+		t.Fatalf("first resource = %q, want cpu", cpu.Resource)
+	}
+	if cpu.HasFull {
+		t.Error("cpu has no full line on older kernels, but HasFull is set")
+	}
+	if cpu.Some.Avg10 != 1.5 || cpu.Some.Avg60 != 0.75 || cpu.Some.Avg300 != 0.25 {
+		t.Errorf("cpu some averages = %v/%v/%v, want 1.5/0.75/0.25", cpu.Some.Avg10, cpu.Some.Avg60, cpu.Some.Avg300)
+	}
+	if cpu.Some.Total != 123456*time.Microsecond {
+		t.Errorf("cpu some total = %v, want 123.456ms", cpu.Some.Total)
+	}
+
+	memory := m.Pressure[1]
+	if !memory.HasFull {
+		t.Fatal("memory has a full line, but HasFull is not set")
+	}
+	if memory.Full.Avg10 != 0.1 || memory.Full.Total != 2*time.Millisecond {
+		t.Errorf("memory full = %+v, want avg10 0.1 and total 2ms", memory.Full)
+	}
+	if memory.Some.StallPercent != 0 {
+		t.Errorf("first sample has a stall percent of %v, want 0", memory.Some.StallPercent)
+	}
+}
+
+func TestPressureCollectorStallPercent(t *testing.T) {
+	root := t.TempDir()
+	write := func(some, full int) {
+		t.Helper()
+		for _, resource := range pressureResources {
+			content := "some avg10=0.00 avg60=0.00 avg300=0.00 total=" + strconv.Itoa(some) + "\n" +
+				"full avg10=0.00 avg60=0.00 avg300=0.00 total=" + strconv.Itoa(full) + "\n"
+			if err := os.WriteFile(filepath.Join(root, resource), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	c := &pressureCollector{root: root}
+	var m Metrics
+	write(1000000, 500000)
+	if err := c.Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	// Pretend the first sample was taken a second ago: 250ms of "some" and 100ms of
+	// "full" stall since then is 25% and 10%
+	c.prevTime = c.prevTime.Add(-time.Second)
+	write(1250000, 600000)
+	if err := c.Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	for _, p := range m.Pressure {
+		if math.Abs(p.Some.StallPercent-25) > 1 {
+			t.Errorf("%s some stall = %.2f%%, want about 25%%", p.Resource, p.Some.StallPercent)
+		}
+		if math.Abs(p.Full.StallPercent-10) > 1 {
+			t.Errorf("%s full stall = %.2f%%, want about 10%%", p.Resource, p.Full.StallPercent)
+		}
+	}
+
+	// A counter going backwards, as after a checkpoint/restore, is no stall rather than a huge one
+	write(0, 0)
+	if err := c.Collect(&m); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if got := m.Pressure[0].Some.StallPercent; got != 0 {
+		t.Errorf("stall after a counter reset = %v, want 0", got)
+	}
+}
+
+func TestPressureCollectorUnavailable(t *testing.T) {
+	c := &pressureCollector{root: filepath.Join(t.TempDir(), "missing")}
+	var m Metrics
+	if err := c.Collect(&m); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Collect on a missing root = %v, want ErrUnavailable", err)
+	}
+}
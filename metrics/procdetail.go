@@ -0,0 +1,221 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Unlimited is the Soft or Hard value of a ProcessLimit with no limit set
+const Unlimited uint64 = math.MaxUint64
+
+// ProcessDetail is everything the detail pane shows about one process. Sections the monitor
+// isn't allowed to read (another user's environment, say) are left empty with the reason in Errors.
+type ProcessDetail struct {
+	ProcessInfo
+	Args    []string
+	Exe     string
+	Cwd     string
+	Environ []string
+	Limits  []ProcessLimit
+	FDs     []OpenFD
+	Memory  []MappingSummary // by mapping kind, largest RSS first
+	Threads []ThreadInfo
+
+	// From /proc/<pid>/io: bytes that actually went to or came from storage
+	ReadBytes           uint64
+	WriteBytes          uint64
+	ReadBytesPerSec     float64
+	WriteBytesPerSec    float64
+	VoluntarySwitches   int64
+	InvoluntarySwitches int64
+
+	Errors map[string]error // keyed by section: "args", "exe", "cwd", "environ", "limits", "fds", "memory", "threads", "io"
+}
+
+// ProcessLimit is one resource limit, as in /proc/<pid>/limits
+type ProcessLimit struct {
+	Name string
+	Unit string
+	Soft uint64
+	Hard uint64
+}
+
+// OpenFD is an open file descriptor and what it refers to, e.g. a path, "socket:[1234]" or "pipe:[5678]"
+type OpenFD struct {
+	FD     uint64
+	Target string
+}
+
+// MappingSummary totals the memory mappings of one kind: heap, stack, anon, file, shmem or other
+type MappingSummary struct {
+	Kind  string
+	Count int
+	Size  uint64
+	RSS   uint64
+	PSS   uint64
+	Swap  uint64
+}
+
+// ThreadInfo is one thread of a process
+type ThreadInfo struct {
+	TID        int32
+	Name       string
+	State      string
+	CPUPercent float64
+	CPUSeconds float64
+}
+
+// limitNames maps gopsutil's resource numbers to what /proc/<pid>/limits calls them, with units
+var limitNames = map[int32][2]string{
+	process.RLIMIT_CPU:        {"Max cpu time", "seconds"},
+	process.RLIMIT_FSIZE:      {"Max file size", "bytes"},
+	process.RLIMIT_DATA:       {"Max data size", "bytes"},
+	process.RLIMIT_STACK:      {"Max stack size", "bytes"},
+	process.RLIMIT_CORE:       {"Max core file size", "bytes"},
+	process.RLIMIT_RSS:        {"Max resident set", "bytes"},
+	process.RLIMIT_NPROC:      {"Max processes", "processes"},
+	process.RLIMIT_NOFILE:     {"Max open files", "files"},
+	process.RLIMIT_MEMLOCK:    {"Max locked memory", "bytes"},
+	process.RLIMIT_AS:         {"Max address space", "bytes"},
+	process.RLIMIT_LOCKS:      {"Max file locks", "locks"},
+	process.RLIMIT_SIGPENDING: {"Max pending signals", "signals"},
+	process.RLIMIT_MSGQUEUE:   {"Max msgqueue size", "bytes"},
+	process.RLIMIT_NICE:       {"Max nice priority", ""},
+	process.RLIMIT_RTPRIO:     {"Max realtime priority", ""},
+	process.RLIMIT_RTTIME:     {"Max realtime timeout", "us"},
+}
+
+// ProcessInspector reads ProcessDetail for one process at a time, remembering the previous
+// reading so it can turn the I/O and thread CPU counters into rates
+type ProcessInspector struct {
+	pid         int32
+	created     int64
+	prevTime    time.Time
+	prevRead    uint64
+	prevWrite   uint64
+	prevThreads map[int32]float64
+}
+
+// NewProcessInspector returns an inspector with no previous reading
+func NewProcessInspector() *ProcessInspector {
+	return &ProcessInspector{}
+}
+
+// Inspect reads the details of a process. It only fails if the process no longer exists.
+func (i *ProcessInspector) Inspect(pid int32) (ProcessDetail, error) {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return ProcessDetail{}, fmt.Errorf("process %d: %w", pid, err)
+	}
+	created, err := p.CreateTime()
+	if err != nil {
+		return ProcessDetail{}, fmt.Errorf("process %d: %w", pid, err)
+	}
+
+	// A different process, or the same PID reused, starts the rates again
+	now := time.Now()
+	var elapsed float64
+	if i.pid == pid && i.created == created && !i.prevTime.IsZero() {
+		elapsed = now.Sub(i.prevTime).Seconds()
+	} else {
+		i.prevThreads = nil
+	}
+	i.pid, i.created, i.prevTime = pid, created, now
+
+	d := ProcessDetail{Errors: make(map[string]error)}
+	d.PID = pid
+	d.StartTime = time.UnixMilli(created)
+	d.Name, _ = p.Name()
+	d.PPID, _ = p.Ppid()
+	d.User, _ = p.Username()
+	d.Cgroup = readProcessCgroup(pid)
+	if mem, err := p.MemoryInfo(); err == nil {
+		d.RSS, d.VMS = mem.RSS, mem.VMS
+	}
+	if status, err := p.Status(); err == nil && len(status) > 0 {
+		d.State = status[0]
+	}
+	if nice, err := p.Nice(); err == nil {
+		d.Nice = niceFromGopsutil(nice)
+	}
+
+	d.Args, err = p.CmdlineSlice()
+	d.record("args", err)
+	d.Exe, err = p.Exe()
+	d.record("exe", err)
+	d.Cwd, err = p.Cwd()
+	d.record("cwd", err)
+	d.Environ, err = p.Environ()
+	d.record("environ", err)
+
+	limits, err := p.Rlimit()
+	d.record("limits", err)
+	for _, l := range limits {
+		names, ok := limitNames[l.Resource]
+		if !ok {
+			continue
+		}
+		d.Limits = append(d.Limits, ProcessLimit{Name: names[0], Unit: names[1], Soft: l.Soft, Hard: l.Hard})
+	}
+
+	files, err := p.OpenFiles()
+	d.record("fds", err)
+	for _, f := range files {
+		d.FDs = append(d.FDs, OpenFD{FD: f.Fd, Target: f.Path})
+	}
+	sort.Slice(d.FDs, func(a, b int) bool { return d.FDs[a].FD < d.FDs[b].FD })
+
+	d.Memory, err = readMappingSummary(pid)
+	d.record("memory", err)
+
+	d.Threads, err = readThreads(pid)
+	d.record("threads", err)
+	d.Threads = i.threadRates(d.Threads, elapsed)
+	d.ProcessInfo.Threads = int32(len(d.Threads))
+
+	if switches, err := p.NumCtxSwitches(); err == nil {
+		d.VoluntarySwitches, d.InvoluntarySwitches = switches.Voluntary, switches.Involuntary
+	}
+
+	io, err := p.IOCounters()
+	d.record("io", err)
+	if err == nil {
+		d.ReadBytes, d.WriteBytes = io.ReadBytes, io.WriteBytes
+		if elapsed > 0 {
+			d.ReadBytesPerSec = rate(i.prevRead, io.ReadBytes, elapsed)
+			d.WriteBytesPerSec = rate(i.prevWrite, io.WriteBytes, elapsed)
+		}
+		i.prevRead, i.prevWrite = io.ReadBytes, io.WriteBytes
+	}
+
+	return d, nil
+}
+
+// threadRates fills in each thread's CPU% from its CPU time at the previous reading
+func (i *ProcessInspector) threadRates(threads []ThreadInfo, elapsed float64) []ThreadInfo {
+	current := make(map[int32]float64, len(threads))
+	for t := range threads {
+		current[threads[t].TID] = threads[t].CPUSeconds
+		if prev, ok := i.prevThreads[threads[t].TID]; ok && elapsed > 0 && threads[t].CPUSeconds >= prev {
+			threads[t].CPUPercent = (threads[t].CPUSeconds - prev) / elapsed * 100
+		}
+	}
+	i.prevThreads = current
+	return threads
+}
+
+// record notes why a section couldn't be read
+func (d *ProcessDetail) record(section string, err error) {
+	if err == nil {
+		return
+	}
+	// gopsutil's sentinel for this lives in an internal package, so match its message
+	if err.Error() == "not implemented yet" {
+		err = fmt.Errorf("%w on this platform", ErrUnavailable)
+	}
+	d.Errors[section] = err
+}
@@ -0,0 +1,140 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// clockTicks is USER_HZ, the unit of the CPU times in /proc/<pid>/stat; it is 100 on every
+// mainstream architecture
+const clockTicks = 100
+
+// readMappingSummary totals /proc/<pid>/smaps by mapping kind
+func readMappingSummary(pid int32) ([]MappingSummary, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/smaps", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	byKind := make(map[string]*MappingSummary)
+	var current *MappingSummary
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if !strings.HasSuffix(fields[0], ":") {
+			// Header: address perms offset dev inode [path]
+			kind := mappingKind(strings.Join(fields[min(5, len(fields)):], " "))
+			if byKind[kind] == nil {
+				byKind[kind] = &MappingSummary{Kind: kind}
+			}
+			current = byKind[kind]
+			current.Count++
+			continue
+		}
+		if current == nil || len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "Size:":
+			current.Size += kb * 1024
+		case "Rss:":
+			current.RSS += kb * 1024
+		case "Pss:":
+			current.PSS += kb * 1024
+		case "Swap:":
+			current.Swap += kb * 1024
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	summary := make([]MappingSummary, 0, len(byKind))
+	for _, s := range byKind {
+		summary = append(summary, *s)
+	}
+	sort.Slice(summary, func(i, j int) bool { return summary[i].RSS > summary[j].RSS })
+	return summary, nil
+}
+
+// mappingKind classifies a mapping by the path column of /proc/<pid>/maps
+func mappingKind(path string) string {
+	switch {
+	case path == "":
+		return "anon"
+	case path == "[heap]":
+		return "heap"
+	case strings.HasPrefix(path, "[stack"):
+		return "stack"
+	case strings.HasPrefix(path, "[anon"):
+		return "anon"
+	case strings.HasPrefix(path, "/dev/shm/"), strings.HasPrefix(path, "/memfd:"), strings.HasPrefix(path, "/SYSV"):
+		return "shmem"
+	case strings.HasPrefix(path, "/"):
+		return "file"
+	}
+	return "other"
+}
+
+// readThreads lists the threads in /proc/<pid>/task
+func readThreads(pid int32) ([]ThreadInfo, error) {
+	dir := fmt.Sprintf("/proc/%d/task", pid)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	threads := make([]ThreadInfo, 0, len(entries))
+	for _, entry := range entries {
+		tid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		stat, err := os.ReadFile(filepath.Join(dir, entry.Name(), "stat"))
+		if err != nil {
+			continue // the thread exited
+		}
+		t, ok := parseThreadStat(string(stat))
+		if !ok {
+			continue
+		}
+		t.TID = int32(tid)
+		threads = append(threads, t)
+	}
+	sort.Slice(threads, func(i, j int) bool { return threads[i].TID < threads[j].TID })
+	return threads, nil
+}
+
+// parseThreadStat reads the name, state and CPU time from a task's stat line. The name is in
+// parentheses and may itself contain spaces or parentheses, so fields are counted from the last ')'.
+func parseThreadStat(stat string) (ThreadInfo, bool) {
+	openParen, closeParen := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if openParen < 0 || closeParen < openParen {
+		return ThreadInfo{}, false
+	}
+	fields := strings.Fields(stat[closeParen+1:])
+	if len(fields) < 13 {
+		return ThreadInfo{}, false
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	return ThreadInfo{
+		Name:       stat[openParen+1 : closeParen],
+		State:      fields[0],
+		CPUSeconds: float64(utime+stime) / clockTicks,
+	}, true
+}
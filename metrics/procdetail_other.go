@@ -0,0 +1,15 @@
+//go:build !linux
+
+package metrics
+
+import "fmt"
+
+// readMappingSummary needs /proc/<pid>/smaps, which only Linux has
+func readMappingSummary(pid int32) ([]MappingSummary, error) {
+	return nil, fmt.Errorf("%w: memory maps are Linux only", ErrUnavailable)
+}
+
+// readThreads needs /proc/<pid>/task, which only Linux has
+func readThreads(pid int32) ([]ThreadInfo, error) {
+	return nil, fmt.Errorf("%w: thread list is Linux only", ErrUnavailable)
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ProcessInfo is a snapshot of one process
+type ProcessInfo struct {
+	PID        int32
+	PPID       int32
+	User       string
+	State      string // gopsutil status such as "running", "sleep" or "zombie"
+	Nice       int32
+	CPUPercent float64 // of one CPU, so a busy multi-threaded process can exceed 100
+	RSS        uint64
+	VMS        uint64
+	Threads    int32
+	StartTime  time.Time
+
+	// Disk I/O from /proc/<pid>/io, which needs root for other users' processes (Linux only)
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+
+	Name    string
+	Command string // full command line, or the name when that's unreadable
+	Cgroup  string // cgroup path such as "/system.slice/nginx.service" (Linux only)
+}
+
+// processKey tells a process apart from a later one that reuses its PID
+type processKey struct {
+	pid     int32
+	created int64
+}
+
+// processCounters are the cumulative values a process's rates are worked out from
+type processCounters struct {
+	cpuSeconds    float64
+	read, write   uint64
+	hasCPU, hasIO bool
+}
+
+// processCollector lists every process, computing CPU% and I/O rates from the change in the
+// counters between ticks
+type processCollector struct {
+	prev     map[processKey]processCounters
+	prevTime time.Time
+	users    map[int32]string
+}
+
+// NewProcessCollector returns the process list collector, for registries built up from scratch
+func NewProcessCollector() Collector {
+	return &processCollector{}
+}
+
+func (c *processCollector) Name() string { return "processes" }
+
+func (c *processCollector) Collect(m *Metrics) error {
+	procs, err := process.Processes()
+	if err != nil {
+		return fmt.Errorf("list processes: %w", err)
+	}
+	if c.users == nil {
+		c.users = make(map[int32]string)
+	}
+
+	now := time.Now()
+	var elapsed float64
+	if !c.prevTime.IsZero() {
+		elapsed = now.Sub(c.prevTime).Seconds()
+	}
+
+	current := make(map[processKey]processCounters, len(procs))
+	infos := make([]ProcessInfo, 0, len(procs))
+	for _, p := range procs {
+		// Processes can exit while we read them; anything essential missing means skip it
+		created, err := p.CreateTime()
+		if err != nil {
+			continue
+		}
+		name, err := p.Name()
+		if err != nil {
+			continue
+		}
+
+		info := ProcessInfo{
+			PID:       p.Pid,
+			Name:      name,
+			Command:   name,
+			StartTime: time.UnixMilli(created),
+			Cgroup:    readProcessCgroup(p.Pid),
+		}
+		info.PPID, _ = p.Ppid()
+		info.Threads, _ = p.NumThreads()
+		if nice, err := p.Nice(); err == nil {
+			info.Nice = niceFromGopsutil(nice)
+		}
+		if cmdline, err := p.Cmdline(); err == nil && cmdline != "" {
+			info.Command = cmdline
+		}
+		if status, err := p.Status(); err == nil && len(status) > 0 {
+			info.State = status[0]
+		}
+		if mem, err := p.MemoryInfo(); err == nil {
+			info.RSS, info.VMS = mem.RSS, mem.VMS
+		}
+		if uids, err := p.Uids(); err == nil && len(uids) > 0 {
+			info.User = c.lookupUser(uids[0])
+		}
+
+		key := processKey{pid: p.Pid, created: created}
+		var counters processCounters
+		if times, err := p.Times(); err == nil {
+			counters.cpuSeconds, counters.hasCPU = times.User+times.System, true
+		}
+		if io, err := p.IOCounters(); err == nil {
+			counters.read, counters.write, counters.hasIO = io.ReadBytes, io.WriteBytes, true
+		}
+		current[key] = counters
+		if prev, ok := c.prev[key]; ok && elapsed > 0 {
+			if counters.hasCPU && prev.hasCPU && counters.cpuSeconds >= prev.cpuSeconds {
+				info.CPUPercent = (counters.cpuSeconds - prev.cpuSeconds) / elapsed * 100
+			}
+			if counters.hasIO && prev.hasIO {
+				info.ReadBytesPerSec = rate(prev.read, counters.read, elapsed)
+				info.WriteBytesPerSec = rate(prev.write, counters.write, elapsed)
+			}
+		}
+		infos = append(infos, info)
+	}
+	c.prev, c.prevTime = current, now
+
+	m.Processes = infos
+	return nil
+}
+
+// lookupUser resolves a UID to a user name, caching the answer since the lookup can be slow
+func (c *processCollector) lookupUser(uid int32) string {
+	if name, ok := c.users[uid]; ok {
+		return name
+	}
+	name := strconv.Itoa(int(uid))
+	if u, err := user.LookupId(name); err == nil {
+		name = u.Username
+	}
+	c.users[uid] = name
+	return name
+}
@@ -0,0 +1,39 @@
+//go:build linux
+
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// niceFromGopsutil converts gopsutil's nice value, which on Linux is the raw getpriority
+// result (20 - nice), into the usual -20..19 range
+func niceFromGopsutil(raw int32) int32 {
+	return 20 - raw
+}
+
+// readProcessCgroup returns the cgroup a process belongs to. On cgroup v2 that's the single
+// "0::" entry; on v1 the first hierarchy listed is used.
+func readProcessCgroup(pid int32) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return ""
+	}
+	var first string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			return parts[2]
+		}
+		if first == "" {
+			first = parts[2]
+		}
+	}
+	return first
+}
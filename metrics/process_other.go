@@ -0,0 +1,13 @@
+//go:build !linux
+
+package metrics
+
+// niceFromGopsutil passes the value through; only Linux needs converting
+func niceFromGopsutil(raw int32) int32 {
+	return raw
+}
+
+// readProcessCgroup returns "" since cgroups are Linux only
+func readProcessCgroup(pid int32) string {
+	return ""
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import "sort"
+
+// ProcessNode is a process in the parent/child tree, with totals over its whole subtree
+type ProcessNode struct {
+	ProcessInfo
+	Children []*ProcessNode
+
+	SubtreeCPU   float64 // CPU% of this process and all its descendants
+	SubtreeRSS   uint64
+	SubtreeCount int // number of processes in the subtree, including this one
+}
+
+// BuildProcessTree nests processes under their parents and returns the roots, ordered by PID.
+// Processes whose parent isn't in the list become roots, as does one member of any parent
+// loop left behind by PID reuse.
+func BuildProcessTree(procs []ProcessInfo) []*ProcessNode {
+	nodes := make(map[int32]*ProcessNode, len(procs))
+	for _, p := range procs {
+		nodes[p.PID] = &ProcessNode{ProcessInfo: p}
+	}
+
+	var roots []*ProcessNode
+	for _, p := range procs {
+		node := nodes[p.PID]
+		parent, ok := nodes[p.PPID]
+		if !ok || p.PPID == p.PID {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	sortNodes(roots)
+
+	visited := make(map[int32]bool, len(nodes))
+	for _, root := range roots {
+		aggregate(root, visited)
+	}
+
+	// Anything not reached from a root is caught in a parent loop
+	var orphans []*ProcessNode
+	for _, p := range procs {
+		if !visited[p.PID] {
+			node := nodes[p.PID]
+			aggregate(node, visited)
+			orphans = append(orphans, node)
+		}
+	}
+	sortNodes(orphans)
+	return append(roots, orphans...)
+}
+
+// aggregate sums a subtree depth-first, dropping any child already seen so loops can't recurse forever
+func aggregate(node *ProcessNode, visited map[int32]bool) {
+	visited[node.PID] = true
+	node.SubtreeCPU = node.CPUPercent
+	node.SubtreeRSS = node.RSS
+	node.SubtreeCount = 1
+
+	children := node.Children[:0]
+	for _, child := range node.Children {
+		if visited[child.PID] {
+			continue
+		}
+		aggregate(child, visited)
+		node.SubtreeCPU += child.SubtreeCPU
+		node.SubtreeRSS += child.SubtreeRSS
+		node.SubtreeCount += child.SubtreeCount
+		children = append(children, child)
+	}
+	node.Children = children
+	sortNodes(node.Children)
+}
+
+func sortNodes(nodes []*ProcessNode) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].PID < nodes[j].PID })
+}
+
+// FindProcessNode returns the node for pid anywhere under roots
+func FindProcessNode(roots []*ProcessNode, pid int32) *ProcessNode {
+	for _, node := range roots {
+		if node.PID == pid {
+			return node
+		}
+		if found := FindProcessNode(node.Children, pid); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build darwin
+
+package metrics
+
+import (
+	"os"
+	"syscall"
+)
+
+// MaxRSS returns the peak RSS of the largest process in a finished command's tree, as
+// reported by wait4. macOS reports it in bytes.
+func MaxRSS(state *os.ProcessState) (uint64, bool) {
+	usage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0, false
+	}
+	return uint64(usage.Maxrss), true
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !linux
+
+package metrics
+
+import "os"
+
+// MaxRSS isn't reported on this platform
+func MaxRSS(state *os.ProcessState) (uint64, bool) {
+	return 0, false
+}
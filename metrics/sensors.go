@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// SensorChip is one hardware monitoring chip, such as coretemp, k10temp, nct6775 or nvme, with
+// its readings. Thresholds and limits are 0 when the chip doesn't report them.
+type SensorChip struct {
+	Name         string // driver name, e.g. "coretemp"
+	ID           string // where it was found, e.g. "hwmon2"
+	Temperatures []TempSensor
+	Fans         []FanSensor
+	Voltages     []VoltageSensor
+	Power        []PowerSensor
+}
+
+// TempSensor is one temperature reading, in °C
+type TempSensor struct {
+	Label    string
+	Celsius  float64
+	High     float64
+	Critical float64
+}
+
+// FanSensor is one fan's speed
+type FanSensor struct {
+	Label string
+	RPM   float64
+	Min   float64
+}
+
+// VoltageSensor is one voltage rail
+type VoltageSensor struct {
+	Label string
+	Volts float64
+	Min   float64
+	Max   float64
+}
+
+// PowerSensor is one power reading
+type PowerSensor struct {
+	Label string
+	Watts float64
+}
+
+// SensorName is how a sensor is named for -cpu-sensor, e.g. "coretemp/Package id 0"
+func SensorName(chip SensorChip, label string) string {
+	return chip.Name + "/" + label
+}
+
+// cpuSensorPatterns are tried in order when no CPU sensor is configured: the package sensor
+// of Intel's coretemp, AMD's control and die temperatures, then the SoC zones of ARM boards.
+// Anything else, such as an NVMe drive or a Wi-Fi card, isn't taken for the CPU.
+var cpuSensorPatterns = []string{
+	"coretemp/Package id *",
+	"k10temp/Tctl",
+	"k10temp/Tdie",
+	"zenpower/Tdie",
+	"zenpower/Tctl",
+	"k8temp/*",
+	"cpu_thermal/*",
+	"cpu-thermal/*",
+	"soc_thermal/*",
+	"coretemp/Core *",
+	"acpitz/*",
+}
+
+// ValidateSensorPattern checks a -cpu-sensor value, which is a path.Match pattern
+func ValidateSensorPattern(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("bad sensor pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// selectCPUTemp picks the temperature that stands for the CPU: the first matching the
+// configured pattern, or by cpuSensorPatterns when pattern is empty. Where several cores
+// match, as with "coretemp/Core *", the hottest is taken.
+func selectCPUTemp(chips []SensorChip, pattern string) (float64, string, bool) {
+	patterns := cpuSensorPatterns
+	if pattern != "" {
+		patterns = []string{pattern}
+	}
+	for _, p := range patterns {
+		var best float64
+		var name string
+		for _, chip := range chips {
+			for _, t := range chip.Temperatures {
+				if ok, _ := path.Match(p, SensorName(chip, t.Label)); ok && t.Celsius > best {
+					best, name = t.Celsius, SensorName(chip, t.Label)
+				}
+			}
+		}
+		if name != "" {
+			return best, name, true
+		}
+	}
+	return 0, "", false
+}
+
+// coreTemperatures are the per-core readings: coretemp's "Core N" on Intel and k10temp's
+// per-CCD "Tccd N" on AMD
+func coreTemperatures(chips []SensorChip) []TempSensor {
+	var cores []TempSensor
+	for _, chip := range chips {
+		for _, t := range chip.Temperatures {
+			if (chip.Name == "coretemp" && strings.HasPrefix(t.Label, "Core ")) ||
+				(chip.Name == "k10temp" && strings.HasPrefix(t.Label, "Tccd")) {
+				cores = append(cores, t)
+			}
+		}
+	}
+	return cores
+}
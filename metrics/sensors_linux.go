@@ -0,0 +1,127 @@
+//go:build linux
+
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hwmonRoot is where the kernel lists hardware monitoring chips
+const hwmonRoot = "/sys/class/hwmon"
+
+// hwmonInput matches the reading files, e.g. "temp1_input" or "power2_average"
+var hwmonInput = regexp.MustCompile(`^(temp|fan|in|power)(\d+)_(input|average)$`)
+
+// readSensors reads every chip under /sys/class/hwmon. Values are in the units of the sysfs
+// hwmon ABI: millidegrees, RPM, millivolts and microwatts.
+func readSensors() ([]SensorChip, error) {
+	entries, err := os.ReadDir(hwmonRoot)
+	if err != nil || len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no hardware monitoring chips in %s", ErrUnavailable, hwmonRoot)
+	}
+	var chips []SensorChip
+	for _, entry := range entries {
+		chip, ok := readHwmonChip(filepath.Join(hwmonRoot, entry.Name()))
+		if ok {
+			chip.ID = entry.Name()
+			chips = append(chips, chip)
+		}
+	}
+	// hwmon numbering follows driver load order; sort for a stable display
+	sort.SliceStable(chips, func(i, j int) bool { return chips[i].Name < chips[j].Name })
+	return chips, nil
+}
+
+func readHwmonChip(dir string) (SensorChip, bool) {
+	// Older drivers keep their attributes on the device rather than the hwmon node
+	if _, err := os.Stat(filepath.Join(dir, "name")); err != nil {
+		dir = filepath.Join(dir, "device")
+	}
+	name, ok := readSysfsString(filepath.Join(dir, "name"))
+	if !ok {
+		return SensorChip{}, false
+	}
+	chip := SensorChip{Name: name}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return chip, true
+	}
+	type reading struct {
+		kind, file, prefix string
+		index              int
+	}
+	var readings []reading
+	for _, f := range files {
+		match := hwmonInput.FindStringSubmatch(f.Name())
+		if match == nil {
+			continue
+		}
+		prefix := match[1] + match[2]
+		// power*_average stands in only where there's no power*_input
+		if match[3] == "average" {
+			if _, err := os.Stat(filepath.Join(dir, prefix+"_input")); err == nil {
+				continue
+			}
+		}
+		index, _ := strconv.Atoi(match[2])
+		readings = append(readings, reading{kind: match[1], file: f.Name(), prefix: prefix, index: index})
+	}
+	sort.Slice(readings, func(i, j int) bool { return readings[i].index < readings[j].index })
+
+	for _, r := range readings {
+		value, ok := readSysfsFloat(filepath.Join(dir, r.file))
+		if !ok {
+			continue
+		}
+		label, ok := readSysfsString(filepath.Join(dir, r.prefix+"_label"))
+		if !ok {
+			label = r.prefix
+		}
+		limit := func(suffix string, scale float64) float64 {
+			v, _ := readSysfsFloat(filepath.Join(dir, r.prefix+"_"+suffix))
+			return v / scale
+		}
+		switch r.kind {
+		case "temp":
+			chip.Temperatures = append(chip.Temperatures, TempSensor{
+				Label: label, Celsius: value / 1000, High: limit("max", 1000), Critical: limit("crit", 1000),
+			})
+		case "fan":
+			chip.Fans = append(chip.Fans, FanSensor{Label: label, RPM: value, Min: limit("min", 1)})
+		case "in":
+			chip.Voltages = append(chip.Voltages, VoltageSensor{
+				Label: label, Volts: value / 1000, Min: limit("min", 1000), Max: limit("max", 1000),
+			})
+		case "power":
+			chip.Power = append(chip.Power, PowerSensor{Label: label, Watts: value / 1e6})
+		}
+	}
+	return chip, true
+}
+
+func readSysfsString(path string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	value := strings.TrimSpace(string(data))
+	return value, value != ""
+}
+
+// readSysfsFloat reads a numeric attribute; a read error, as a sensor that is switched off
+// gives, counts as missing
+func readSysfsFloat(path string) (float64, bool) {
+	value, ok := readSysfsString(path)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	return n, err == nil
+}
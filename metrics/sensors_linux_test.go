@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeHwmon lays out a hwmon attribute directory from file name to content
+func writeHwmon(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		writeFixture(t, filepath.Join(dir, name), content)
+	}
+}
+
+func TestReadHwmonChip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hwmon3")
+	writeHwmon(t, dir, map[string]string{
+		"name":           "nct6775\n",
+		"temp1_input":    "45500\n",
+		"temp1_label":    "SYSTIN\n",
+		"temp1_max":      "80000\n",
+		"temp1_crit":     "95000\n",
+		"temp10_input":   "38000\n", // sorts after temp2, not after temp1
+		"temp2_input":    "51000\n",
+		"temp3_input":    "", // a sensor that is switched off
+		"temp3_label":    "AUXTIN\n",
+		"fan1_input":     "1250\n",
+		"fan1_min":       "300\n",
+		"fan2_input":     "0\n",
+		"in0_input":      "1216\n",
+		"in0_label":      "Vcore\n",
+		"in0_min":        "1000\n",
+		"in0_max":        "1500\n",
+		"power1_input":   "12500000\n",
+		"power1_average": "99000000\n", // ignored next to power1_input
+		"power2_average": "3000000\n",
+		"temp1_type":     "4\n", // attributes that aren't readings
+		"uevent":         "",
+	})
+
+	chip, ok := readHwmonChip(dir)
+	if !ok {
+		t.Fatal("readHwmonChip found no chip")
+	}
+	want := SensorChip{
+		Name: "nct6775",
+		Temperatures: []TempSensor{
+			{Label: "SYSTIN", Celsius: 45.5, High: 80, Critical: 95},
+			{Label: "temp2", Celsius: 51},
+			{Label: "temp10", Celsius: 38},
+		},
+		Fans: []FanSensor{
+			{Label: "fan1", RPM: 1250, Min: 300},
+			{Label: "fan2", RPM: 0},
+		},
+		Voltages: []VoltageSensor{{Label: "Vcore", Volts: 1.216, Min: 1, Max: 1.5}},
+		Power: []PowerSensor{
+			{Label: "power1", Watts: 12.5},
+			{Label: "power2", Watts: 3},
+		},
+	}
+	if !reflect.DeepEqual(chip, want) {
+		t.Errorf("readHwmonChip =\n%+v\nwant\n%+v", chip, want)
+	}
+}
+
+// Older drivers keep their attributes in the device directory under the hwmon node
+func TestReadHwmonChipDevice(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hwmon0")
+	writeHwmon(t, filepath.Join(dir, "device"), map[string]string{
+		"name":        "it87\n",
+		"temp1_input": "40000\n",
+	})
+	chip, ok := readHwmonChip(dir)
+	if !ok || chip.Name != "it87" || len(chip.Temperatures) != 1 || chip.Temperatures[0].Celsius != 40 {
+		t.Errorf("readHwmonChip = %+v, %v; want it87 with one 40°C reading", chip, ok)
+	}
+
+	if _, ok := readHwmonChip(t.TempDir()); ok {
+		t.Error("readHwmonChip accepted a directory without a name")
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package metrics
+
+import "fmt"
+
+// readSensors needs /sys/class/hwmon, which only Linux has; elsewhere CPU Temp comes from
+// GetCPUTemperature alone
+func readSensors() ([]SensorChip, error) {
+	return nil, fmt.Errorf("%w: hardware monitoring chips are read from /sys/class/hwmon, Linux only", ErrUnavailable)
+}
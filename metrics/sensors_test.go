@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectCPUTemp(t *testing.T) {
+	intel := SensorChip{Name: "coretemp", Temperatures: []TempSensor{
+		{Label: "Package id 0", Celsius: 52},
+		{Label: "Core 0", Celsius: 50},
+		{Label: "Core 1", Celsius: 55},
+	}}
+	amd := SensorChip{Name: "k10temp", Temperatures: []TempSensor{
+		{Label: "Tctl", Celsius: 61},
+		{Label: "Tccd1", Celsius: 58},
+	}}
+	nvme := SensorChip{Name: "nvme", Temperatures: []TempSensor{{Label: "Composite", Celsius: 40}}}
+	wifi := SensorChip{Name: "iwlwifi_1", Temperatures: []TempSensor{{Label: "temp1", Celsius: 45}}}
+	acpi := SensorChip{Name: "acpitz", Temperatures: []TempSensor{{Label: "temp1", Celsius: 30}}}
+	coresOnly := SensorChip{Name: "coretemp", Temperatures: []TempSensor{
+		{Label: "Core 0", Celsius: 47},
+		{Label: "Core 1", Celsius: 49},
+	}}
+
+	tests := []struct {
+		name    string
+		chips   []SensorChip
+		pattern string
+		temp    float64
+		sensor  string
+		ok      bool
+	}{
+		{"intel package", []SensorChip{nvme, intel}, "", 52, "coretemp/Package id 0", true},
+		{"amd control", []SensorChip{wifi, amd, nvme}, "", 61, "k10temp/Tctl", true},
+		{"cores without a package sensor take the hottest", []SensorChip{coresOnly}, "", 49, "coretemp/Core 1", true},
+		{"cpu sensor before acpi", []SensorChip{acpi, amd}, "", 61, "k10temp/Tctl", true},
+		{"acpi as a last resort", []SensorChip{nvme, acpi}, "", 30, "acpitz/temp1", true},
+		{"drives and wifi are not the cpu", []SensorChip{nvme, wifi}, "", 0, "", false},
+		{"no chips", nil, "", 0, "", false},
+		{"configured sensor", []SensorChip{intel, nvme}, "nvme/Composite", 40, "nvme/Composite", true},
+		{"configured pattern takes the hottest", []SensorChip{intel}, "coretemp/Core *", 55, "coretemp/Core 1", true},
+		{"configured sensor missing", []SensorChip{intel}, "k10temp/Tctl", 0, "", false},
+	}
+	for _, tt := range tests {
+		temp, sensor, ok := selectCPUTemp(tt.chips, tt.pattern)
+		if temp != tt.temp || sensor != tt.sensor || ok != tt.ok {
+			t.Errorf("%s: selectCPUTemp = %v, %q, %v; want %v, %q, %v", tt.name, temp, sensor, ok, tt.temp, tt.sensor, tt.ok)
+		}
+	}
+}
+
+func TestCoreTemperatures(t *testing.T) {
+	chips := []SensorChip{
+		{Name: "coretemp", Temperatures: []TempSensor{
+			{Label: "Package id 0"}, {Label: "Core 0"}, {Label: "Core 4"},
+		}},
+		{Name: "k10temp", Temperatures: []TempSensor{
+			{Label: "Tctl"}, {Label: "Tccd1"}, {Label: "Tccd2"},
+		}},
+		{Name: "nvme", Temperatures: []TempSensor{{Label: "Core"}, {Label: "Sensor 1"}}},
+		{Name: "acpitz", Temperatures: []TempSensor{{Label: "temp1"}}},
+	}
+	var got []string
+	for _, c := range coreTemperatures(chips) {
+		got = append(got, c.Label)
+	}
+	want := []string{"Core 0", "Core 4", "Tccd1", "Tccd2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("coreTemperatures = %q, want %q", got, want)
+	}
+}
+
+func TestValidateSensorPattern(t *testing.T) {
+	if err := ValidateSensorPattern("coretemp/Package id *"); err != nil {
+		t.Errorf("valid pattern rejected: %v", err)
+	}
+	if err := ValidateSensorPattern("k10temp/[Tctl"); err == nil {
+		t.Error("unterminated class accepted")
+	}
+}
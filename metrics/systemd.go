@@ -0,0 +1,59 @@
+package metrics
+
+import "strings"
+
+// systemdSlices are the top-level slices whose units are reported; system.slice holds
+// services, user.slice the user sessions and per-user managers
+var systemdSlices = []string{"system.slice", "user.slice"}
+
+// systemdUnitTypes are the unit types systemd gives their own cgroup
+var systemdUnitTypes = []string{".service", ".scope", ".socket", ".mount", ".swap"}
+
+// UnitStats is the resource usage of one systemd unit, taken from its cgroup
+type UnitStats struct {
+	CgroupStats
+	Unit  string // e.g. "nginx.service"
+	Slice string // the cgroup path above the unit, e.g. "system.slice" or "user.slice/user-1000.slice"
+}
+
+// SystemdUnits picks the cgroups systemd made for units under system.slice and user.slice out
+// of a cgroup walk, and names them. It needs nothing but the paths, so it works on any
+// hierarchy the cgroup collector can read, fixtures included. Units nested in another unit,
+// such as those of a user manager, are listed as well as the unit containing them, whose
+// figures include theirs.
+func SystemdUnits(cgroups []CgroupStats) []UnitStats {
+	var units []UnitStats
+	for _, cg := range cgroups {
+		slice, unit, ok := systemdUnit(cg.Path)
+		if !ok {
+			continue
+		}
+		units = append(units, UnitStats{CgroupStats: cg, Unit: unit, Slice: slice})
+	}
+	return units
+}
+
+// systemdUnit splits a cgroup path like "/system.slice/nginx.service" into the slice and the
+// unit, reporting false for cgroups that aren't a unit under one of systemdSlices
+func systemdUnit(path string) (slice, unit string, ok bool) {
+	i := strings.LastIndex(path, "/")
+	parent, unit := strings.TrimPrefix(path[:max(i, 0)], "/"), path[i+1:]
+	if !isUnitName(unit) {
+		return "", "", false
+	}
+	for _, top := range systemdSlices {
+		if parent == top || strings.HasPrefix(parent, top+"/") {
+			return parent, unit, true
+		}
+	}
+	return "", "", false
+}
+
+func isUnitName(name string) bool {
+	for _, suffix := range systemdUnitTypes {
+		if len(name) > len(suffix) && strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
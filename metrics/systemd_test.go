@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSystemdUnit(t *testing.T) {
+	tests := []struct {
+		path        string
+		slice, unit string
+		ok          bool
+	}{
+		{"/system.slice/nginx.service", "system.slice", "nginx.service", true},
+		{"/system.slice/docker-0123abcd.scope", "system.slice", "docker-0123abcd.scope", true},
+		{"/system.slice/dbus.socket", "system.slice", "dbus.socket", true},
+		{"/system.slice/boot.mount", "system.slice", "boot.mount", true},
+		{"/system.slice/dev-sda2.swap", "system.slice", "dev-sda2.swap", true},
+		{"/system.slice/system-getty.slice/getty@tty1.service", "system.slice/system-getty.slice", "getty@tty1.service", true},
+		{"/user.slice/user-1000.slice/session-3.scope", "user.slice/user-1000.slice", "session-3.scope", true},
+		{"/user.slice/user-1000.slice/user@1000.service", "user.slice/user-1000.slice", "user@1000.service", true},
+		{"/user.slice/user-1000.slice/user@1000.service/app.slice/foo.service",
+			"user.slice/user-1000.slice/user@1000.service/app.slice", "foo.service", true},
+		{"/user.slice/user-1000.slice/user@1000.service/app.slice/app-firefox-1234.scope",
+			"user.slice/user-1000.slice/user@1000.service/app.slice", "app-firefox-1234.scope", true},
+
+		// Slices are containers for units, not units
+		{"/system.slice", "", "", false},
+		{"/user.slice/user-1000.slice", "", "", false},
+		{"/user.slice/user-1000.slice/user@1000.service/app.slice", "", "", false},
+
+		// Outside system.slice and user.slice, or not systemd's at all
+		{"/", "", "", false},
+		{"", "", "", false},
+		{"/init.scope", "", "", false},
+		{"/machine.slice/machine-qemu.scope", "", "", false},
+		{"/system.slicex/nginx.service", "", "", false},
+		{"/kubepods/burstable/pod1234/0123abcd", "", "", false},
+		{"/system.slice/nginx.service/worker", "", "", false},
+		{"/system.slice/.service", "", "", false},
+	}
+	for _, tt := range tests {
+		slice, unit, ok := systemdUnit(tt.path)
+		if slice != tt.slice || unit != tt.unit || ok != tt.ok {
+			t.Errorf("systemdUnit(%q) = %q, %q, %v; want %q, %q, %v", tt.path, slice, unit, ok, tt.slice, tt.unit, tt.ok)
+		}
+	}
+}
+
+func TestSystemdUnits(t *testing.T) {
+	cgroups := []CgroupStats{
+		{Path: "/"},
+		{Path: "/init.scope"},
+		{Path: "/system.slice"},
+		{Path: "/system.slice/nginx.service", Tasks: 5},
+		{Path: "/user.slice"},
+		{Path: "/user.slice/user-1000.slice"},
+		{Path: "/user.slice/user-1000.slice/user@1000.service", Tasks: 3},
+		{Path: "/user.slice/user-1000.slice/user@1000.service/app.slice"},
+		{Path: "/user.slice/user-1000.slice/user@1000.service/app.slice/foo.service", Tasks: 1},
+	}
+	var got []string
+	for _, u := range SystemdUnits(cgroups) {
+		got = append(got, u.Slice+" "+u.Unit)
+		if u.Unit == "nginx.service" && u.Tasks != 5 {
+			t.Errorf("nginx.service lost its cgroup figures: %+v", u.CgroupStats)
+		}
+	}
+	want := []string{
+		"system.slice nginx.service",
+		"user.slice/user-1000.slice user@1000.service",
+		"user.slice/user-1000.slice/user@1000.service/app.slice foo.service",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SystemdUnits = %q, want %q", got, want)
+	}
+}
@@ -3,38 +3,41 @@
 package metrics
 
 import (
-	"strings"
-
-	"github.com/shirou/gopsutil/v3/host"
+	"path"
+	"path/filepath"
 )
 
-// getCPUTemperature gets CPU temperature on Linux using lm-sensors via gopsutil
+// thermalRoot is where the kernel lists thermal zones
+const thermalRoot = "/sys/class/thermal"
+
+// thermalZonePatterns are the zone types that stand for the CPU, best first: Intel's package
+// sensor, the CPU and SoC zones of ARM boards, then ACPI's zone, which is usually on the board
+var thermalZonePatterns = []string{"x86_pkg_temp", "cpu*", "soc*", "acpitz"}
+
+// GetCPUTemperature reads the CPU temperature from the thermal zones in /sys/class/thermal, for
+// machines whose CPU sensor has no hwmon chip, as on some ARM boards
 func GetCPUTemperature() float64 {
-	temps, err := host.SensorsTemperatures()
-	if err != nil {
-		return 0.0
-	}
+	return readThermalZoneTemp(thermalRoot)
+}
 
-	for _, t := range temps {
-		// Try multiple common CPU temperature sensor patterns
-		sensorKey := strings.ToLower(t.SensorKey)
-		if strings.Contains(sensorKey, "package id 0") ||
-			strings.Contains(sensorKey, "cpu") ||
-			strings.Contains(sensorKey, "tctl") || // AMD CPU temperature
-			strings.Contains(sensorKey, "core") ||
-			strings.Contains(sensorKey, "die") ||
-			strings.Contains(sensorKey, "thermal") ||
-			strings.Contains(sensorKey, "acpi") || // ACPI thermal zones
-			strings.Contains(sensorKey, "temp") { // Generic temperature sensors
-			return t.Temperature
+// readThermalZoneTemp returns the hottest zone of the first type in thermalZonePatterns that
+// has a reading, or 0
+func readThermalZoneTemp(root string) float64 {
+	zones, _ := filepath.Glob(filepath.Join(root, "thermal_zone*"))
+	for _, pattern := range thermalZonePatterns {
+		var hottest float64
+		for _, zone := range zones {
+			kind, ok := readSysfsString(filepath.Join(zone, "type"))
+			if match, _ := path.Match(pattern, kind); !ok || !match {
+				continue
+			}
+			if millidegrees, ok := readSysfsFloat(filepath.Join(zone, "temp")); ok && millidegrees/1000 > hottest {
+				hottest = millidegrees / 1000
+			}
+		}
+		if hottest > 0 {
+			return hottest
 		}
 	}
-
-	// If no CPU-specific sensor found, try to use the first available temperature sensor
-	// (often ACPI thermal zones can be a reasonable fallback)
-	if len(temps) > 0 {
-		return temps[0].Temperature
-	}
-
-	return 0.0
+	return 0
 }
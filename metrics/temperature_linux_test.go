@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestReadThermalZoneTemp(t *testing.T) {
+	zones := func(t *testing.T, zones map[string][2]string) string {
+		root := t.TempDir()
+		for zone, z := range zones {
+			writeHwmon(t, filepath.Join(root, zone), map[string]string{"type": z[0] + "\n", "temp": z[1] + "\n"})
+		}
+		return root
+	}
+
+	tests := []struct {
+		name  string
+		zones map[string][2]string
+		want  float64
+	}{
+		{"package sensor first", map[string][2]string{
+			"thermal_zone0": {"acpitz", "30000"},
+			"thermal_zone1": {"x86_pkg_temp", "55000"},
+			"thermal_zone2": {"iwlwifi_1", "45000"},
+		}, 55},
+		{"hottest cpu zone", map[string][2]string{
+			"thermal_zone0": {"cpu0-thermal", "48000"},
+			"thermal_zone1": {"cpu1-thermal", "52500"},
+			"thermal_zone2": {"gpu-thermal", "60000"},
+		}, 52.5},
+		{"acpi as a last resort", map[string][2]string{
+			"thermal_zone0": {"acpitz", "27800"},
+			"thermal_zone1": {"INT3400 Thermal", "20000"},
+		}, 27.8},
+		{"nothing that stands for the cpu", map[string][2]string{
+			"thermal_zone0": {"iwlwifi_1", "45000"},
+		}, 0},
+		{"no zones", nil, 0},
+	}
+	for _, tt := range tests {
+		if got := readThermalZoneTemp(zones(t, tt.zones)); got != tt.want {
+			t.Errorf("%s: readThermalZoneTemp = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package metrics
+
+// UserStats totals the processes of one user
+type UserStats struct {
+	User             string
+	Processes        int
+	Threads          int
+	CPUPercent       float64 // of one CPU, like ProcessInfo.CPUPercent
+	RSS              uint64  // summed, so memory shared between the processes is counted more than once
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+}
+
+// GroupByUser totals processes per owning user, in order of each user's first process.
+// Processes whose owner couldn't be read are grouped under "?".
+func GroupByUser(procs []ProcessInfo) []UserStats {
+	index := make(map[string]int)
+	var users []UserStats
+	for _, p := range procs {
+		name := p.User
+		if name == "" {
+			name = "?"
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(users)
+			index[name] = i
+			users = append(users, UserStats{User: name})
+		}
+		u := &users[i]
+		u.Processes++
+		u.Threads += int(p.Threads)
+		u.CPUPercent += p.CPUPercent
+		u.RSS += p.RSS
+		u.ReadBytesPerSec += p.ReadBytesPerSec
+		u.WriteBytesPerSec += p.WriteBytesPerSec
+	}
+	return users
+}
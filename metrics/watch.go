@@ -0,0 +1,195 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// WatchStats totals a watched process and its descendants
+type WatchStats struct {
+	PID     int32
+	Started time.Time // when watching began
+	Exited  bool      // the watched process is gone
+
+	Processes             []ProcessInfo // the watched process and its live descendants
+	CPUPercent            float64
+	RSS                   uint64
+	Threads               int
+	FDs                   int
+	ReadBytesPerSec       float64
+	WriteBytesPerSec      float64
+	ContextSwitchesPerSec float64
+
+	// Running totals since watching began, including descendants that have since exited
+	PeakRSS         uint64 // highest RSS of the whole tree at any sample
+	CPUSeconds      float64
+	ReadBytes       uint64
+	WriteBytes      uint64
+	ContextSwitches uint64
+	ProcessesSeen   int
+}
+
+// watchCounters are one process's cumulative counters at the last sample
+type watchCounters struct {
+	cpu      float64
+	read     uint64
+	write    uint64
+	switches uint64
+}
+
+// watchCollector follows one process and its descendants. It reads the process list from the
+// "processes" collector, so it must be registered after it.
+type watchCollector struct {
+	pid      int32
+	spawned  bool
+	started  time.Time
+	prev     map[processKey]watchCounters
+	baseline map[processKey]watchCounters // counters from before watching began, not counted
+	exited   watchCounters                // final counters of descendants that have gone
+	seen     map[processKey]bool
+	prevTime time.Time
+	peakRSS  uint64
+}
+
+// NewWatchCollector returns a collector that fills Metrics.Watch for pid and its descendants.
+// spawned says the monitor started pid itself, so all of its CPU time and I/O count towards the
+// totals; for a process it attached to, only what happens after the first sample counts.
+func NewWatchCollector(pid int32, spawned bool) Collector {
+	return &watchCollector{
+		pid:      pid,
+		spawned:  spawned,
+		started:  time.Now(),
+		prev:     make(map[processKey]watchCounters),
+		baseline: make(map[processKey]watchCounters),
+		seen:     make(map[processKey]bool),
+	}
+}
+
+func (c *watchCollector) Name() string { return "watch" }
+
+func (c *watchCollector) Collect(m *Metrics) error {
+	now := time.Now()
+	firstSample := c.prevTime.IsZero()
+	var elapsed float64
+	if !firstSample {
+		elapsed = now.Sub(c.prevTime).Seconds()
+	}
+	c.prevTime = now
+
+	stats := WatchStats{PID: c.pid, Started: c.started, Exited: true}
+	members := watchedProcesses(m.Processes, c.pid)
+	current := make(map[processKey]watchCounters, len(members))
+	var rateRead, rateWrite, rateSwitches float64
+
+	for _, info := range members {
+		// A zombie has finished, even if its parent hasn't reaped it yet
+		if info.PID == c.pid && info.State != process.Zombie {
+			stats.Exited = false
+		}
+		key := processKey{pid: info.PID, created: info.StartTime.UnixMilli()}
+		counters, fds := readWatchCounters(info.PID)
+		current[key] = counters
+		if !c.seen[key] {
+			c.seen[key] = true
+			// Attached processes have history from before we started; only count what follows
+			if firstSample && !c.spawned {
+				c.baseline[key] = counters
+			}
+		}
+		if prev, ok := c.prev[key]; ok && elapsed > 0 {
+			rateRead += rate(prev.read, counters.read, elapsed)
+			rateWrite += rate(prev.write, counters.write, elapsed)
+			rateSwitches += rate(prev.switches, counters.switches, elapsed)
+		}
+
+		stats.Processes = append(stats.Processes, info)
+		stats.CPUPercent += info.CPUPercent
+		stats.RSS += info.RSS
+		stats.Threads += int(info.Threads)
+		stats.FDs += fds
+	}
+
+	// Whatever has gone since the last sample keeps its final counters in the totals
+	for key, counters := range c.prev {
+		if _, ok := current[key]; !ok {
+			c.exited = c.exited.add(counters.sub(c.baseline[key]))
+			delete(c.baseline, key)
+		}
+	}
+	c.prev = current
+
+	totals := c.exited
+	for key, counters := range current {
+		totals = totals.add(counters.sub(c.baseline[key]))
+	}
+	c.peakRSS = max(c.peakRSS, stats.RSS)
+
+	stats.ReadBytesPerSec, stats.WriteBytesPerSec = rateRead, rateWrite
+	stats.ContextSwitchesPerSec = rateSwitches
+	stats.PeakRSS = c.peakRSS
+	stats.CPUSeconds = totals.cpu
+	stats.ReadBytes, stats.WriteBytes = totals.read, totals.write
+	stats.ContextSwitches = totals.switches
+	stats.ProcessesSeen = len(c.seen)
+	m.Watch = stats
+
+	if stats.Exited && len(c.seen) == 0 {
+		return fmt.Errorf("process %d not found", c.pid)
+	}
+	return nil
+}
+
+// watchedProcesses returns pid and everything descended from it
+func watchedProcesses(procs []ProcessInfo, pid int32) []ProcessInfo {
+	children := make(map[int32][]ProcessInfo)
+	var members []ProcessInfo
+	for _, p := range procs {
+		if p.PID == pid {
+			members = append(members, p)
+		} else {
+			children[p.PPID] = append(children[p.PPID], p)
+		}
+	}
+	for i := 0; i < len(members); i++ {
+		members = append(members, children[members[i].PID]...)
+		delete(children, members[i].PID) // guards against PPID cycles
+	}
+	return members
+}
+
+// readWatchCounters reads the cumulative counters and open FD count for one process. Anything
+// unreadable counts as zero.
+func readWatchCounters(pid int32) (watchCounters, int) {
+	var counters watchCounters
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return counters, 0
+	}
+	if times, err := p.Times(); err == nil {
+		counters.cpu = times.User + times.System
+	}
+	if io, err := p.IOCounters(); err == nil {
+		counters.read, counters.write = io.ReadBytes, io.WriteBytes
+	}
+	if switches, err := p.NumCtxSwitches(); err == nil {
+		counters.switches = uint64(switches.Voluntary + switches.Involuntary)
+	}
+	fds, _ := p.NumFDs()
+	return counters, int(fds)
+}
+
+func (a watchCounters) add(b watchCounters) watchCounters {
+	return watchCounters{cpu: a.cpu + b.cpu, read: a.read + b.read, write: a.write + b.write, switches: a.switches + b.switches}
+}
+
+// sub returns how far the counters have moved on from an earlier reading
+func (a watchCounters) sub(b watchCounters) watchCounters {
+	return watchCounters{
+		cpu:      max(a.cpu-b.cpu, 0),
+		read:     uint64(delta(b.read, a.read)),
+		write:    uint64(delta(b.write, a.write)),
+		switches: uint64(delta(b.switches, a.switches)),
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/krisfur/go-resource-monitor/dashboard"
+	"github.com/krisfur/go-resource-monitor/metrics"
+)
+
+// outputLimit is how much of a spawned command's output is kept for the Output panel
+const outputLimit = 64 * 1024
+
+// stopTimeout is how long a spawned command gets to exit after an interrupt before it is
+// killed, and how long its output is read after it exits
+const stopTimeout = 3 * time.Second
+
+// outputBuffer keeps the tail of a command's combined stdout and stderr
+type outputBuffer struct {
+	mu        sync.Mutex
+	data      []byte
+	truncated bool
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if len(b.data) > outputLimit {
+		b.data = b.data[len(b.data)-outputLimit:]
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+func (b *outputBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.data)
+}
+
+// runWatch implements "go-resource-monitor watch", returning the exit code to use
+func runWatch(args []string) int {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	pid := flags.Int("pid", 0, "attach to this running process")
+	outputPath := flags.String("o", "", "write the command's output to this file instead of the Output panel")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: go-resource-monitor watch -pid N")
+		fmt.Fprintln(flags.Output(), "       go-resource-monitor watch [-o file] -- command [args...]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	command := flags.Args()
+
+	if (*pid == 0) == (len(command) == 0) {
+		flags.Usage()
+		return 2
+	}
+	if *pid != 0 {
+		return attachWatch(int32(*pid))
+	}
+	return spawnWatch(command, *outputPath)
+}
+
+// attachWatch follows a process that's already running
+func attachWatch(pid int32) int {
+	detail, err := metrics.NewProcessInspector().Inspect(pid)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		return 1
+	}
+	title := strings.Join(detail.Args, " ")
+	if title == "" {
+		title = detail.Name
+	}
+
+	stats, _ := watch(pid, false, dashboard.WatchOptions{Title: title})
+	// Quitting before the first sample leaves nothing to sum up
+	if stats.Started.IsZero() {
+		fmt.Fprintln(os.Stderr, "watch: stopped before the first sample")
+		return 0
+	}
+	dashboard.PrintWatchSummary(os.Stderr, title, stats, time.Since(stats.Started), nil)
+	return 0
+}
+
+// spawnWatch starts a command and follows it until it exits
+func spawnWatch(command []string, outputPath string) int {
+	cmd := exec.Command(command[0], command[1:]...)
+	startOwnGroup(cmd)
+	// Background processes it leaves behind may hold the output open; don't wait on them
+	cmd.WaitDelay = stopTimeout
+	opts := dashboard.WatchOptions{Title: strings.Join(command, " ")}
+
+	// The dashboard owns the terminal, so the command's output goes to a file or the Output panel
+	output := &outputBuffer{}
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		cmd.Stdout, cmd.Stderr = f, f
+	} else {
+		cmd.Stdout, cmd.Stderr = output, output
+		opts.Output = output.String
+	}
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		if errors.Is(err, exec.ErrNotFound) {
+			return 127
+		}
+		return 126
+	}
+	waited := make(chan struct{})
+	var wall time.Duration
+	go func() {
+		cmd.Wait()
+		wall = time.Since(start)
+		close(waited)
+	}()
+
+	stats, userQuit := watch(int32(cmd.Process.Pid), true, opts)
+	if userQuit {
+		// Leaving the command running with nowhere to write its output would be worse
+		if err := interruptGroup(cmd.Process); err != nil {
+			killGroup(cmd.Process)
+		}
+		select {
+		case <-waited:
+		case <-time.After(stopTimeout):
+			killGroup(cmd.Process)
+		}
+	}
+	<-waited
+
+	if outputPath == "" {
+		if output.truncated {
+			fmt.Fprintf(os.Stdout, "[output truncated to the last %d KiB]\n", outputLimit/1024)
+		}
+		fmt.Fprint(os.Stdout, output.String())
+	}
+	dashboard.PrintWatchSummary(os.Stderr, opts.Title, stats, wall, cmd.ProcessState)
+	if code := cmd.ProcessState.ExitCode(); code >= 0 {
+		return code
+	}
+	return 1 // killed by a signal
+}
+
+// watch runs a watch collector for pid and shows the watch dashboard. Only the process list
+// it works from is collected; the rest of the system readings would go unused.
+func watch(pid int32, spawned bool, opts dashboard.WatchOptions) (metrics.WatchStats, bool) {
+	registry := metrics.NewRegistry()
+	registry.Register(metrics.NewProcessCollector())
+	registry.Register(metrics.NewWatchCollector(pid, spawned))
+
+	metricsChan := make(chan metrics.Metrics)
+	quitChan := make(chan struct{})
+	go func() {
+		registry.Run(metricsChan, quitChan)
+		close(metricsChan) // ends the dashboard's metrics loop
+	}()
+
+	return dashboard.StartWatchUI(metricsChan, quitChan, opts)
+}
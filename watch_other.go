@@ -0,0 +1,21 @@
+//go:build !linux && !darwin
+
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// startOwnGroup does nothing where there are no Unix process groups
+func startOwnGroup(cmd *exec.Cmd) {}
+
+// interruptGroup interrupts p alone, which fails where interrupts can't be sent
+func interruptGroup(p *os.Process) error {
+	return p.Signal(os.Interrupt)
+}
+
+// killGroup kills p alone
+func killGroup(p *os.Process) error {
+	return p.Kill()
+}
@@ -0,0 +1,25 @@
+//go:build linux || darwin
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// startOwnGroup puts a spawned command in a process group of its own, so stopping it reaches
+// whatever it started too, such as the children of a shell wrapper
+func startOwnGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interruptGroup sends SIGINT to the process group p leads
+func interruptGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGINT)
+}
+
+// killGroup sends SIGKILL to the process group p leads
+func killGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGKILL)
+}